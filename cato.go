@@ -5,28 +5,38 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/doc/comment"
 	"go/parser"
 	"go/printer"
 	"go/token"
+	"go/types"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/cs3org/cato/exporter"
 	_ "github.com/cs3org/cato/exporter/drivers/loader"
 	"github.com/cs3org/cato/exporter/drivers/registry"
+	"github.com/cs3org/cato/exporter/utils"
 	"github.com/cs3org/cato/resources"
 )
 
 type structInfo struct {
 	StructDef  *ast.StructType
 	StructName string
+	Doc        *ast.CommentGroup
+	TypeParams []string
+	// Parent is the struct an inline anonymous struct is declared in.
+	Parent string
 }
 
-var namedTags = []string{"xml", "mapstructure", "json"}
+var namedTags = []string{"json", "mapstructure", "xml", "yaml", "toml"}
+
+var envTags = []string{"env", "envconfig"}
 
 func listGoFiles(rootPath string) ([]string, error) {
 	goFileRegex, _ := regexp.Compile(`^.+\.go$`)
@@ -71,7 +81,7 @@ func getLineInitialPositions(filePath string) ([]int, error) {
 
 func getLineNumber(lineNos []int, pos int) (int, error) {
 	for i, n := range lineNos {
-		if pos <= n {
+		if pos < n {
 			return i, nil
 		}
 	}
@@ -81,7 +91,10 @@ func getLineNumber(lineNos []int, pos int) (int, error) {
 
 func getNestedConfigDefaults(configs map[string][]*resources.FieldInfo) string {
 	defaults := ""
-	for _, fields := range configs {
+	for s, fields := range configs {
+		if resources.Section(s, fields).Kind != resources.StructSection {
+			continue
+		}
 		for _, f := range fields {
 			defaults += f.FieldName + " = " + f.DefaultValue + "\n"
 		}
@@ -89,91 +102,315 @@ func getNestedConfigDefaults(configs map[string][]*resources.FieldInfo) string {
 	return defaults
 }
 
-func parseStruct(structDef *ast.StructType, catoTag, rootPath, filePath string, fset *token.FileSet, lineNos []int) ([]*resources.FieldInfo, error) {
+func getDescription(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	// Text strips the comment markers and directives such as //cato:group
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+// newCommentParser returns a parser of the doc comments of a file, resolving
+// the links to the packages it imports.
+func newCommentParser(file *ast.File) *comment.Parser {
+	imports := map[string]string{}
+	for _, imp := range file.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = importPath
+	}
+	return &comment.Parser{
+		LookupPackage: func(name string) (string, bool) {
+			importPath, ok := imports[name]
+			return importPath, ok
+		},
+	}
+}
+
+func parseDoc(p *comment.Parser, doc *ast.CommentGroup) *comment.Doc {
+	if doc == nil || strings.TrimSpace(doc.Text()) == "" {
+		return nil
+	}
+	return p.Parse(doc.Text())
+}
+
+func parseStruct(structDef *ast.StructType, structName string, doc *ast.CommentGroup, commentParser *comment.Parser, overlay map[string]*overlayEntry, conf *resources.CatoConfig, rootPath, filePath string, fset *token.FileSet, lineNos []int) ([]*resources.FieldInfo, error) {
 	configs := []*resources.FieldInfo{}
+	goNames := []string{}
+	section := &resources.SectionInfo{Name: structName, Kind: resources.StructSection}
+
+	// the directives can be set by overlays for the structs without any doc
+	pos := structDef.Pos()
+	if doc != nil {
+		pos = doc.Pos()
+	}
+	directives := parseDirectives(doc)
+	applyOverlay(overlay, structName, directives)
+	section.Group = directives["group"]
+	if w, ok := directives["weight"]; ok {
+		lineNumber, err := getLineNumber(lineNos, int(pos))
+		if err != nil {
+			return nil, err
+		}
+		section.Weight = parseWeight(w, conf, filePath, lineNumber)
+	}
+
+	if len(directives) > 0 {
+		lineNumber, err := getLineNumber(lineNos, int(pos))
+		if err != nil {
+			return nil, err
+		}
+		checkDirectives(directives, func(name string) bool { return structDirectives[name] }, conf, filePath, lineNumber)
+	}
+	if _, ignored := directives["ignore"]; ignored {
+		return configs, nil
+	}
+
+	// //cato:doc opts all the exported fields of the struct in, as does the
+	// older //cato:untagged
+	_, untagged := directives["untagged"]
+	_, opted := directives["doc"]
+	untagged = untagged || opted || documentsUntagged(structName, conf)
 
 	for _, field := range structDef.Fields.List {
-		if field.Tag != nil {
+		// any directive other than //cato:ignore documents the field
+		fieldDirs := parseDirectives(field.Doc)
+		goName := fieldGoName(field)
+		var overlayDesc string
+		if e := applyOverlay(overlay, structName+"."+goName, fieldDirs); e != nil {
+			overlayDesc = e.Description
+		}
+		if _, ignored := fieldDirs["ignore"]; ignored {
+			continue
+		}
 
-			tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
-			configTag := tag.Get(catoTag)
+		if field.Tag != nil || untagged || len(fieldDirs) > 0 {
+
+			var tag reflect.StructTag
+			if field.Tag != nil {
+				tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+			}
+			configTag := tag.Get(conf.CustomTag)
 
-			if configTag != "" {
+			// the exported fields of the structs documented without the
+			// custom tag are skipped if it's set to -
+			_, hasConfigTag := tag.Lookup(conf.CustomTag)
+			if untagged && configTag == "-" {
+				continue
+			}
+			untaggedField := !hasConfigTag && len(field.Names) > 0 &&
+				(len(fieldDirs) > 0 || untagged && field.Names[0].IsExported())
+
+			if configTag != "" || untaggedField {
 				// get field.Type as string
 				var typeNameBuf bytes.Buffer
 				err := printer.Fprint(&typeNameBuf, fset, field.Type)
 				if err != nil {
 					return nil, fmt.Errorf("error decoding struct field name: %w", err)
 				}
+				if inlineStruct(field.Type) != nil {
+					typeNameBuf.Reset()
+					typeNameBuf.WriteString(inlineTypeName(field.Type, structName+"."+goName))
+				}
 
-				var fieldName string
-				for _, namedTag := range namedTags {
-					if t := tag.Get(namedTag); t != "" {
-						fieldName = strings.Split(t, ",")[0]
+				lineNumber, err := getLineNumber(lineNos, int(field.Pos()))
+				if err != nil {
+					return nil, err
+				}
+
+				checkDirectives(fieldDirs, func(name string) bool { return fieldDirectives[name] || docsAttributes[name] }, conf, filePath, lineNumber)
+
+				fieldName := getFieldName(tag, goName, conf, filePath, lineNumber)
+
+				var envName string
+				for _, envTag := range envTags {
+					if t := tag.Get(envTag); t != "" && envName == "" {
+						envName = strings.Split(t, ",")[0]
 					}
 				}
-				if fieldName == "" {
-					fieldName = field.Names[0].Name
+
+				// trailing comments are used if the field has no doc comment
+				comments := field.Doc
+				if getDescription(comments) == "" {
+					comments = field.Comment
+				}
+				desc := getDescription(comments)
+				fieldDoc := parseDoc(commentParser, comments)
+				if overlayDesc != "" {
+					desc = strings.Join(strings.Fields(overlayDesc), " ")
+					fieldDoc = commentParser.Parse(overlayDesc)
 				}
 
-				var desc string
-				if field.Doc != nil {
-					comments := []string{}
-					for _, c := range field.Doc.List {
-						c.Text = strings.ReplaceAll(c.Text, "//", "")
-						c.Text = strings.ReplaceAll(c.Text, "/*", "")
-						c.Text = strings.ReplaceAll(c.Text, "*/", "")
-						c.Text = strings.Join(strings.Fields(c.Text), " ")
-						comments = append(comments, c.Text)
+				docs := &docsTag{attrs: map[string]string{}}
+				if !untaggedField {
+					docs = parseDocsTag(configTag)
+				}
+				applyDirectives(docs, fieldDirs)
+
+				// libraries such as creasty/defaults and envconfig read the
+				// default values from their own tag
+				defaultVal, hasDefault := tag.Lookup("default")
+
+				// references to constants are resolved once the whole
+				// package is parsed, and take the place of the positional
+				// default value
+				defaultRef, isRef := docs.attrs["default"]
+				splitVals := docs.values
+				if isRef {
+					switch len(splitVals) {
+					case 1:
+						splitVals = []string{defaultRef, splitVals[0]}
+					case 2:
+						splitVals = []string{splitVals[0], defaultRef, splitVals[1]}
 					}
-					desc = strings.Join(comments, " ")
 				}
 
-				var defaultVal string
-
-				switch splitVals := strings.Split(configTag, ";"); len(splitVals) {
+				switch len(splitVals) {
 				case 1:
 					defaultVal = splitVals[0]
 				case 2:
 					defaultVal = splitVals[0]
-					desc = splitVals[1]
+					desc, fieldDoc = splitVals[1], nil
 				case 3:
 					fieldName = splitVals[0]
 					defaultVal = splitVals[1]
-					desc = splitVals[2]
+					desc, fieldDoc = splitVals[2], nil
+				}
+				if name, ok := fieldDirs["name"]; ok && len(splitVals) < 3 {
+					fieldName = name
+				}
+				hasDefault = hasDefault || len(docs.values) > 0
+				if isRef {
+					defaultVal, hasDefault = defaultRef, false
+				}
+
+				sensitive, unmarked := isSensitive(docs.attrs)
+				if sensitive {
+					// defaults of secrets are often credentials used in
+					// development, which shouldn't leak into the docs
+					defaultVal, defaultRef, hasDefault = "", "", false
+				} else if !unmarked && (secretNameRegex.MatchString(goName) || secretNameRegex.MatchString(fieldName)) {
+					report(conf, filePath, lineNumber, "%s looks like a secret but isn't marked as sensitive", fieldName)
 				}
 
 				if strings.HasPrefix(defaultVal, "url:") {
 					driverName := strings.Split(path.Base(strings.TrimPrefix(defaultVal, "url:")), ".")[0]
-					configs, err := getConfigsToDocument(path.Join(rootPath, strings.TrimPrefix(defaultVal, "url:")), catoTag, rootPath)
+					configs, err := getConfigsToDocument(path.Join(rootPath, strings.TrimPrefix(defaultVal, "url:")), conf, rootPath, nil)
 					if err != nil {
 						return nil, err
 					}
 					defaultVal = "url:" + driverName + ":" + getNestedConfigDefaults(configs)
-				} else if typeNameBuf.String() == "string" {
+				} else if typeNameBuf.String() == "string" && hasDefault {
 					defaultVal = fmt.Sprintf("\"%s\"", defaultVal)
 				}
 
-				lineNumber, err := getLineNumber(lineNos, int(field.Pos()))
-				if err != nil {
-					return nil, err
-				}
-
 				configs = append(configs, &resources.FieldInfo{
 					FieldName:    fieldName,
 					DefaultValue: defaultVal,
 					Description:  desc,
-					DataType:     typeNameBuf.String(),
-					LineNumber:   lineNumber,
+					Doc:          fieldDoc,
+					// the default values of the fields without the custom
+					// tag can't be known without running the code
+					DefaultUnknown: untaggedField && !hasDefault && !isRef,
+					DataType:       typeNameBuf.String(),
+					LineNumber:     lineNumber,
+					DefaultRef:     defaultRef,
+					EnvName:        envName,
+					Constraints:    getConstraints(tag, docs.attrs),
+					Since:          docs.attrs["since"],
+					Deprecation:    getDeprecation(docs.attrs),
+					Sensitive:      sensitive,
+					Visibility:     getVisibility(docs.attrs, conf, filePath, lineNumber),
+					Unit:           docs.attrs["unit"],
+					Group:          docs.attrs["group"],
+					Weight:         parseWeight(docs.attrs["weight"], conf, filePath, lineNumber),
+					Section:        section,
 				})
+				goNames = append(goNames, goName)
 			}
 		}
 	}
 
+	resolveRelations(configs, goNames, conf, filePath)
 	return configs, nil
 }
 
-func getConfigsToDocument(filePath, catoTag, rootPath string) (map[string][]*resources.FieldInfo, error) {
+// fieldGoName returns the name of a field in the code, which is the name of
+// the type of embedded fields.
+func fieldGoName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	expr := field.Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.SelectorExpr:
+			return t.Sel.Name
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return types.ExprString(expr)
+		}
+	}
+}
+
+// inlineStruct returns the anonymous struct declared inline as the type of a
+// field, or as the type of its pointers, elements or map values.
+func inlineStruct(expr ast.Expr) *ast.StructType {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t
+	case *ast.StarExpr:
+		return inlineStruct(t.X)
+	case *ast.ArrayType:
+		return inlineStruct(t.Elt)
+	case *ast.MapType:
+		return inlineStruct(t.Value)
+	}
+	return nil
+}
+
+// inlineTypeName returns the type of a field declaring an inline struct, with
+// the name of its section in place of the struct, e.g. []Config.Options.
+func inlineTypeName(expr ast.Expr, name string) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + inlineTypeName(t.X, name)
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return "[" + types.ExprString(t.Len) + "]" + inlineTypeName(t.Elt, name)
+		}
+		return "[]" + inlineTypeName(t.Elt, name)
+	case *ast.MapType:
+		return "map[" + types.ExprString(t.Key) + "]" + inlineTypeName(t.Value, name)
+	}
+	return name
+}
+
+// documentsUntagged reports whether all the exported fields of the struct are
+// documented, including the ones without the custom tag.
+func documentsUntagged(structName string, conf *resources.CatoConfig) bool {
+	for _, s := range conf.UntaggedStructs {
+		if s == structName || s == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func getConfigsToDocument(filePath string, conf *resources.CatoConfig, rootPath string, overlays overlays) (map[string][]*resources.FieldInfo, error) {
 	fset := token.NewFileSet()
 	fileTree, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
 	if err != nil {
@@ -189,30 +426,154 @@ func getConfigsToDocument(filePath, catoTag, rootPath string) (map[string][]*res
 	configs := map[string][]*resources.FieldInfo{}
 
 	ast.Inspect(fileTree, func(node ast.Node) bool {
-		spec, ok := node.(*ast.TypeSpec)
-		if !ok {
+		decl, ok := node.(*ast.GenDecl)
+		if !ok || decl.Tok != token.TYPE {
 			return true
 		}
-		s, ok := spec.Type.(*ast.StructType)
-		if !ok {
-			return true
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.TypeSpec)
+			s, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			// the doc of a single type is attached to the declaration
+			doc := spec.Doc
+			if doc == nil && len(decl.Specs) == 1 {
+				doc = decl.Doc
+			}
+			var typeParams []string
+			if spec.TypeParams != nil {
+				for _, p := range spec.TypeParams.List {
+					for _, n := range p.Names {
+						typeParams = append(typeParams, n.Name)
+					}
+				}
+			}
+			structList = append(structList, &structInfo{s, spec.Name.Name, doc, typeParams, ""})
 		}
-		structList = append(structList, &structInfo{s, spec.Name.Name})
 		return false
 	})
 
+	commentParser := newCommentParser(fileTree)
+	overlay := overlays.forPackage(packageImportPath(filePath, fileTree.Name.Name))
+	// inline anonymous structs are documented as nested sections, named after
+	// the struct and field they're declared in
+	for i := 0; i < len(structList); i++ {
+		s := structList[i]
+		for _, field := range s.StructDef.Fields.List {
+			if inline := inlineStruct(field.Type); inline != nil && len(field.Names) > 0 {
+				structList = append(structList, &structInfo{inline, s.StructName + "." + field.Names[0].Name, nil, nil, s.StructName})
+			}
+		}
+	}
+
 	for _, s := range structList {
-		c, err := parseStruct(s.StructDef, catoTag, rootPath, filePath, fset, lineNos)
+		c, err := parseStruct(s.StructDef, s.StructName, s.Doc, commentParser, overlay, conf, rootPath, filePath, fset, lineNos)
 		if err != nil {
 			return nil, err
 		}
 		if len(c) > 0 {
+			// all the fields of a struct share its section
+			c[0].Section.TypeParams = s.TypeParams
 			configs[s.StructName] = c
 		}
 	}
+
+	// the inline structs are only documented along with their field, as the
+	// keys of their fields are nested in its key; parents come first
+	for _, s := range structList {
+		if s.Parent == "" {
+			continue
+		}
+		referenced := false
+		for _, f := range configs[s.Parent] {
+			referenced = referenced || utils.StructRef(f.DataType, configs) == s.StructName
+		}
+		if !referenced {
+			delete(configs, s.StructName)
+		}
+	}
+
+	if conf.DocumentFlags {
+		flags, err := getFlagsToDocument(fileTree, fset, lineNos)
+		if err != nil {
+			return nil, err
+		}
+		if len(flags) > 0 {
+			configs[resources.FlagsSectionName] = flags
+		}
+	}
+
+	if len(conf.OptionTypes) > 0 {
+		options, err := getOptionsToDocument(fileTree, conf.OptionTypes, fset, lineNos)
+		if err != nil {
+			return nil, err
+		}
+		if len(options) > 0 {
+			configs[resources.OptionsSectionName] = options
+		}
+	}
+
+	if len(conf.DefaultFuncs) > 0 {
+		defaults, err := getDefaultsToDocument(fileTree, conf.DefaultFuncs, fset, lineNos)
+		if err != nil {
+			return nil, err
+		}
+		if len(defaults) > 0 {
+			configs[resources.DefaultsSectionName] = defaults
+		}
+	}
 	return configs, nil
 }
 
+// toEnvName converts the name of a field into the one of an environment
+// variable, splitting it into words as the naming policies do, e.g. HTTPAddr
+// into HTTP_ADDR.
+func toEnvName(name string) string {
+	name = strings.NewReplacer("-", "_", ".", "_").Replace(name)
+	return strings.ToUpper(strings.Join(splitWords(name), "_"))
+}
+
+// resolveEnvNames prefixes the names of the environment variables according to
+// the scheme in conf. The fields of the nested structs are prefixed with the
+// name of the field referring to them, unless the nesting scheme is "none".
+func resolveEnvNames(configs map[string][]*resources.FieldInfo, conf *resources.CatoConfig) {
+	done := map[string]bool{}
+
+	var walk func(s, prefix string)
+	walk = func(s, prefix string) {
+		if done[s] {
+			return
+		}
+		done[s] = true
+		for _, f := range configs[s] {
+			if ref := utils.StructRef(f.DataType, configs); ref != "" {
+				childPrefix := prefix
+				if conf.EnvNesting != "none" {
+					name := f.EnvName
+					if name == "" {
+						name = toEnvName(f.FieldName)
+					}
+					childPrefix = prefix + name + "_"
+				}
+				// structs can't be set through a single variable
+				f.EnvName = ""
+				walk(ref, childPrefix)
+			} else if f.EnvName != "" {
+				f.EnvName = prefix + f.EnvName
+			}
+		}
+	}
+
+	for _, root := range utils.Roots(configs) {
+		walk(root, conf.EnvPrefix)
+	}
+	// structs only reachable through cycles
+	for _, s := range utils.SortedSections(configs) {
+		walk(s, conf.EnvPrefix)
+	}
+}
+
 func getDriver(c *resources.CatoConfig) (exporter.ConfigExporter, error) {
 	if f, ok := registry.NewFuncs[c.Driver]; ok {
 		return f(c.DriverConfig[c.Driver])
@@ -230,6 +591,10 @@ func GenerateDocumentation(rootPath string, conf *resources.CatoConfig) (map[str
 		conf.CustomTag = "docs"
 	}
 
+	if err := checkNamingPolicy(conf.NamingPolicy); err != nil {
+		return nil, fmt.Errorf("cato: %w", err)
+	}
+
 	fileList, err := listGoFiles(rootPath)
 	if err != nil {
 		return nil, fmt.Errorf("cato: error listing root path: %w", err)
@@ -242,19 +607,60 @@ func GenerateDocumentation(rootPath string, conf *resources.CatoConfig) (map[str
 		exportConfigs = false
 	}
 
+	overlays, err := loadOverlays(conf.Overlays, rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("cato: %w", err)
+	}
+
 	filesConfigs := map[string]map[string][]*resources.FieldInfo{}
 	for _, file := range fileList {
-		configs, err := getConfigsToDocument(file, conf.CustomTag, rootPath)
+		configs, err := getConfigsToDocument(file, conf, rootPath, overlays)
 		if err != nil {
 			return nil, fmt.Errorf("cato: error parsing go file: %w", err)
 		}
+		resolveEnvNames(configs, conf)
+
+		if len(configs) > 0 {
+			filesConfigs[file] = configs
+		}
+	}
+
+	overlays.reportUnmatched(conf)
+
+	// the constants and types of the fields can be declared in other files of
+	// the package
+	consts, err := getPackageConsts(fileList, filesConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("cato: error parsing go file: %w", err)
+	}
+	resolveDefaultRefs(filesConfigs, consts, conf)
+	resolveEnums(filesConfigs, consts)
+
+	// the registered defaults can be spread across the files of a package
+	if len(conf.DefaultFuncs) > 0 {
+		mergeRegisteredDefaults(filesConfigs, conf)
+	}
+
+	if conf.DeprecationReport != "" {
+		if err := writeDeprecationReport(filesConfigs, rootPath, conf.DeprecationReport); err != nil {
+			return nil, fmt.Errorf("cato: error writing deprecation report: %w", err)
+		}
+	}
 
-		if exportConfigs && len(configs) > 0 {
+	if exportConfigs {
+		levels, ok := conf.Visibility[conf.Driver]
+		if !ok {
+			levels = defaultVisibility
+		}
+		for _, file := range fileList {
+			configs := filterVisibility(filesConfigs[file], levels)
+			if len(configs) == 0 {
+				continue
+			}
 			err = exporterDriver.ExportConfigs(configs, file, rootPath)
 			if err != nil {
 				return nil, fmt.Errorf("cato: error writing documentation: %w", err)
 			}
-			filesConfigs[file] = configs
 		}
 	}
 	return filesConfigs, nil
@@ -0,0 +1,70 @@
+package cato
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+const commentsSource = `package comments
+
+import "net/http"
+
+type Config struct {
+	// The address of the server, see https://example.com/docs.
+	Address string ` + "`docs:\"localhost\"`" + `
+	// The client used to reach the server, a [http.Client].
+	//
+	// Supported schemes:
+	//   - http
+	//   - https
+	//
+	// For example:
+	//
+	//	client: default
+	Client string ` + "`docs:\"default\"`" + `
+	// Ignored in favour of the description in the tag.
+	Timeout int ` + "`docs:\"10;The timeout of the requests\"`" + `
+}
+`
+
+func TestDocComments(t *testing.T) {
+	rootPath := writeSource(t, "comments.go", commentsSource)
+
+	configs, err := GenerateDocumentation(rootPath, &resources.CatoConfig{})
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	fields := configs[filepath.Join(rootPath, "comments.go")]["Config"]
+
+	if d := fields[0].Description; d != "The address of the server, see https://example.com/docs." {
+		t.Errorf("unexpected description of Address: %q", d)
+	}
+
+	summary, details := utils.FormatDoc(fields[1], "markdown")
+	if summary != "The client used to reach the server, a [http.Client](https://pkg.go.dev/net/http#Client)." {
+		t.Errorf("unexpected markdown summary of Client: %q", summary)
+	}
+	for _, s := range []string{"Supported schemes:", "  - http\n  - https", "\tclient: default"} {
+		if !strings.Contains(details, s) {
+			t.Errorf("expected the markdown details of Client to contain %q, got %q", s, details)
+		}
+	}
+
+	summary, details = utils.FormatDoc(fields[1], "html")
+	if !strings.Contains(summary, `<a href="https://pkg.go.dev/net/http#Client">http.Client</a>`) {
+		t.Errorf("unexpected html summary of Client: %q", summary)
+	}
+	for _, s := range []string{"<li>https", "<pre>client: default"} {
+		if !strings.Contains(details, s) {
+			t.Errorf("expected the html details of Client to contain %q, got %q", s, details)
+		}
+	}
+
+	if fields[2].Doc != nil || fields[2].Description != "The timeout of the requests" {
+		t.Errorf("expected the description of Timeout to be taken from its tag, got %q", fields[2].Description)
+	}
+}
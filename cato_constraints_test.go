@@ -0,0 +1,79 @@
+package cato
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+func TestConstraints(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	fields := map[string]*resources.FieldInfo{}
+	for _, s := range configs["examples/filesystem.go"] {
+		for _, f := range s {
+			fields[f.FieldName] = f
+		}
+	}
+
+	if c := fields["LogLevel"].Constraints; !reflect.DeepEqual(c.OneOf, []string{"debug", "info", "warn", "error"}) {
+		t.Errorf("unexpected constraints for LogLevel: %+v", c)
+	}
+
+	f := fields["max_file_size"]
+	expected := resources.Constraints{Required: true, Min: "1", Max: "1073741824"}
+	if !reflect.DeepEqual(f.Constraints, expected) {
+		t.Errorf("unexpected constraints for max_file_size: %+v", f.Constraints)
+	}
+	if f.DefaultValue != "1048576" {
+		t.Errorf("expected the default of max_file_size to be read from the default tag, got %q", f.DefaultValue)
+	}
+}
+
+const attributeWordsSource = `package words
+
+type Config struct {
+	Password string ` + "`docs:\"secret\"`" + `
+	Default  string ` + "`docs:\"default\"`" + `
+	Required string ` + "`docs:\"required\"`" + `
+	Path     string ` + "`docs:\"x;required\"`" + `
+	Token    string ` + "`docs:\"changeme;secret\"`" + `
+	Limit    int    ` + "`docs:\"max=10\"`" + `
+}
+`
+
+func TestAttributeWords(t *testing.T) {
+	rootPath := writeSource(t, "words.go", attributeWordsSource)
+	configs, err := GenerateDocumentation(rootPath, &resources.CatoConfig{})
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	fields := configs[filepath.Join(rootPath, "words.go")]["Config"]
+
+	// a single word is the default value, even if it names an attribute
+	for i, expected := range []string{`"secret"`, `"default"`, `"required"`} {
+		if f := fields[i]; f.DefaultValue != expected || f.Constraints.Required || f.Sensitive {
+			t.Errorf("expected %s to default to %s, got %+v", f.FieldName, expected, f)
+		}
+	}
+
+	// bare words following a positional value and key=value pairs are attributes
+	if f := fields[3]; f.DefaultValue != `"x"` || !f.Constraints.Required {
+		t.Errorf("expected Path to be required and default to x, got %+v", f)
+	}
+	if f := fields[4]; !f.Sensitive || f.Description == "secret" {
+		t.Errorf("expected Token to be sensitive, got %+v", f)
+	}
+	if f := fields[5]; f.Constraints.Max != "10" || f.DefaultValue != "" {
+		t.Errorf("expected Limit to have a maximum of 10, got %+v", f)
+	}
+}
@@ -0,0 +1,78 @@
+package cato
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+func TestRegisteredDefaults(t *testing.T) {
+
+	rootPath := "examples/"
+	diagnostics := []*resources.Diagnostic{}
+	conf := &resources.CatoConfig{
+		Driver:       "markdown",
+		DefaultFuncs: []string{"SetDefault"},
+		DriverConfig: map[string]map[string]interface{}{
+			"markdown": map[string]interface{}{
+				"ReferenceBase": "https://github.com/cs3org/cato/tree/master/examples",
+			},
+		},
+		ReportDiagnostic: func(d *resources.Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		},
+	}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	defaults := configs["examples/defaults.go"][resources.DefaultsSectionName]
+	if len(defaults) != 1 || defaults[0].FieldName != "uploads.chunk_size" || defaults[0].DataType != "int" {
+		t.Errorf("expected only uploads.chunk_size to be left unmatched, got %+v", defaults)
+	}
+
+	// uploads.chunk_size isn't documented in any struct
+	expected := []*resources.Diagnostic{
+		{File: "examples/defaults.go", Line: 17, Message: "default registered for uploads.chunk_size, which isn't documented in any struct"},
+	}
+	if !reflect.DeepEqual(diagnostics, expected) {
+		t.Errorf("unexpected diagnostics: %v", diagnostics)
+	}
+}
+
+func TestMissingDefaults(t *testing.T) {
+
+	rootPath := "examples/"
+	diagnostics := map[string]*resources.Diagnostic{}
+	conf := &resources.CatoConfig{
+		DefaultFuncs:          []string{"SetDefault"},
+		ReportMissingDefaults: true,
+		ReportDiagnostic: func(d *resources.Diagnostic) {
+			diagnostics[d.Message] = d
+		},
+	}
+
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	// the documented keys without a registered default are reported on the
+	// line of their field
+	expected := map[string]int{
+		"no default registered for documented key enablelogging":       7,
+		"no default registered for documented key uploads.disable_tus": 35,
+	}
+	for msg, line := range expected {
+		if d, ok := diagnostics[msg]; !ok || d.File != "examples/filesystem.go" || d.Line != line {
+			t.Errorf("expected %q to be reported at examples/filesystem.go:%d, got %v", msg, line, d)
+		}
+	}
+	for _, key := range []string{"cachedirectory", "uploads.http_prefix"} {
+		if d, ok := diagnostics["no default registered for documented key "+key]; ok {
+			t.Errorf("unexpected diagnostic for the registered key %s: %v", key, d)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package cato
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+func TestDeprecation(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		DeprecationReport: "deprecations.md",
+	}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	fields := map[string]*resources.FieldInfo{}
+	for _, f := range configs["examples/filesystem.go"]["UploadConfig"] {
+		fields[f.FieldName] = f
+	}
+	if d := fields["disable_tus"].Deprecation; d == nil || d.Message != "TUS is always enabled" || d.RemovedIn != "2.0" {
+		t.Errorf("unexpected deprecation of disable_tus: %+v", d)
+	}
+	// the replacement implies the deprecation
+	if d := fields["prefix"].Deprecation; d == nil || d.ReplacedBy != "http_prefix" || fields["prefix"].Since != "0.9" {
+		t.Errorf("unexpected deprecation of prefix: %+v", d)
+	}
+	if fields["workers"].Deprecation != nil || fields["workers"].Since != "1.1" {
+		t.Errorf("expected workers to be introduced in 1.1 and not deprecated")
+	}
+
+	report, err := os.ReadFile("examples/deprecations.md")
+	if err != nil {
+		t.Fatalf("error reading the report: %v", err)
+	}
+	if n := strings.Count(string(report), "| UploadConfig."); n != 2 {
+		t.Errorf("expected 2 deprecated fields in the report, got %d", n)
+	}
+}
@@ -0,0 +1,94 @@
+package cato
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+const directivesSource = `package directives
+
+type Server struct {
+	// The address to listen on
+	//
+	//cato:doc
+	//cato:default localhost:9142
+	//cato:group Networking
+	Address string ` + "`json:\"address\"`" + `
+	//cato:name read_timeout
+	//cato:default 30s
+	//cato:since 1.2
+	Timeout string
+	// Overridden by the tag
+	//
+	//cato:group Networking
+	//cato:default 8
+	Workers int ` + "`docs:\"4;group=Pool\"`" + `
+	//cato:ignore
+	Debug bool ` + "`docs:\"false\"`" + `
+	//cato:frobnicate
+	Other string
+	Undocumented string
+}
+
+// Client is documented as a whole.
+//
+//cato:doc
+type Client struct {
+	Endpoint string
+	Retries  int
+}
+
+// Internal isn't documented.
+//
+//cato:ignore
+type Internal struct {
+	Secret string ` + "`docs:\"s3cr3t;sensitive\"`" + `
+}
+`
+
+func TestDirectives(t *testing.T) {
+	rootPath := writeSource(t, "directives.go", directivesSource)
+
+	diagnostics := []*resources.Diagnostic{}
+	conf := &resources.CatoConfig{
+		ReportDiagnostic: func(d *resources.Diagnostic) { diagnostics = append(diagnostics, d) },
+	}
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	file := configs[filepath.Join(rootPath, "directives.go")]
+
+	server := file["Server"]
+	expected := []struct{ name, def, group, desc string }{
+		{"address", `"localhost:9142"`, "Networking", "The address to listen on"},
+		{"read_timeout", `"30s"`, "", ""},
+		{"Workers", "4", "Pool", "Overridden by the tag"},
+		{"Other", "", "", ""},
+	}
+	if len(server) != len(expected) {
+		t.Fatalf("expected %d fields in Server, got %d", len(expected), len(server))
+	}
+	for i, f := range server {
+		e := expected[i]
+		if f.FieldName != e.name || f.DefaultValue != e.def || f.Group != e.group || f.Description != e.desc {
+			t.Errorf("unexpected field %d of Server: %+v", i, f)
+		}
+	}
+	if server[1].Since != "1.2" {
+		t.Errorf("expected read_timeout to be documented since 1.2, got %q", server[1].Since)
+	}
+
+	if client := file["Client"]; len(client) != 2 || !client[0].DefaultUnknown {
+		t.Errorf("expected the fields of Client to be documented, got %+v", client)
+	}
+	if _, ok := file["Internal"]; ok {
+		t.Errorf("expected Internal to be ignored")
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Line != 22 {
+		t.Errorf("expected //cato:frobnicate to be reported, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,90 @@
+package cato
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+const enumsSource = `package enums
+
+type Config struct {
+	Level  Level   ` + "`docs:\"1\"`" + `
+	Scopes []Scope ` + "`docs:\"[read]\"`" + `
+}
+
+type Level int
+
+const (
+	Debug Level = iota - 1
+	Info // the default level
+	Warn
+	_
+	Error = Warn + 2
+)
+
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite       = Scope("write")
+)
+`
+
+func TestEnums(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	var logFormat *resources.FieldInfo
+	for _, f := range configs["examples/filesystem.go"]["FileSystem"] {
+		if f.FieldName == "LogFormat" {
+			logFormat = f
+		}
+	}
+	expected := []resources.EnumValue{
+		{Name: "LogFormatText", Value: `"text"`, Description: "LogFormatText writes the logs as human readable lines."},
+		{Name: "LogFormatJSON", Value: `"json"`, Description: "LogFormatJSON writes every log as a JSON object."},
+	}
+	if !reflect.DeepEqual(logFormat.Enum, expected) {
+		t.Errorf("unexpected allowed values for LogFormat: %+v", logFormat.Enum)
+	}
+	if logFormat.DefaultValue != `"text"` {
+		t.Errorf("expected the default of LogFormat to be quoted, got %s", logFormat.DefaultValue)
+	}
+}
+
+func TestEnumConstants(t *testing.T) {
+	rootPath := writeSource(t, "enums.go", enumsSource)
+
+	configs, err := GenerateDocumentation(rootPath, &resources.CatoConfig{})
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	fields := configs[filepath.Join(rootPath, "enums.go")]["Config"]
+	values := func(enum []resources.EnumValue) []string {
+		v := []string{}
+		for _, e := range enum {
+			v = append(v, e.Name+"="+e.Value)
+		}
+		return v
+	}
+	// Error is untyped
+	if v := values(fields[0].Enum); !reflect.DeepEqual(v, []string{"Debug=-1", "Info=0", "Warn=1"}) {
+		t.Errorf("unexpected allowed values for Level: %v", v)
+	}
+	if fields[0].Enum[1].Description != "the default level" {
+		t.Errorf("expected the trailing comment to describe Info, got %q", fields[0].Enum[1].Description)
+	}
+	if v := values(fields[1].Enum); !reflect.DeepEqual(v, []string{`ScopeRead="read"`, `ScopeWrite="write"`}) {
+		t.Errorf("unexpected allowed values for Scope: %v", v)
+	}
+}
@@ -0,0 +1,57 @@
+package cato
+
+import (
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+func TestEnv(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		Driver:    "env",
+		EnvPrefix: "FS_",
+	}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	envNames := map[string]string{}
+	for _, fields := range configs["examples/filesystem.go"] {
+		for _, f := range fields {
+			envNames[f.FieldName] = f.EnvName
+		}
+	}
+	expected := map[string]string{
+		"CacheDirectory": "FS_CACHE_DIRECTORY",
+		"Uploads":        "",
+		"http_prefix":    "FS_UPLOADS_HTTP_PREFIX",
+		"disable_tus":    "",
+	}
+	for k, v := range expected {
+		if envNames[k] != v {
+			t.Errorf("EnvName of %s: expected %q, got %q", k, v, envNames[k])
+		}
+	}
+}
+
+func TestEnvNames(t *testing.T) {
+	expected := map[string]string{
+		"CacheDirectory":  "CACHE_DIRECTORY",
+		"http_prefix":     "HTTP_PREFIX",
+		"max-file-size":   "MAX_FILE_SIZE",
+		"grpc.address":    "GRPC_ADDRESS",
+		"HTTPAddr":        "HTTP_ADDR",
+		"UseTLS":          "USE_TLS",
+		"ÄÖUser":          "ÄÖ_USER",
+		"größeDesPuffers": "GRÖßE_DES_PUFFERS",
+	}
+	for name, env := range expected {
+		if got := toEnvName(name); got != env {
+			t.Errorf("toEnvName(%q): expected %q, got %q", name, env, got)
+		}
+	}
+}
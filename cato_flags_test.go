@@ -0,0 +1,41 @@
+package cato
+
+import (
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+func TestFlags(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		Driver:        "markdown",
+		DocumentFlags: true,
+		DriverConfig: map[string]map[string]interface{}{
+			"markdown": map[string]interface{}{
+				"ReferenceBase": "https://github.com/cs3org/cato/tree/master/examples",
+			},
+		},
+	}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	flags := configs["examples/flags.go"][resources.FlagsSectionName]
+	if len(flags) != 4 {
+		t.Fatalf("expected 4 flags, got %d", len(flags))
+	}
+	if f := flags[0]; f.FieldName != "config" || f.DefaultValue != `"/etc/filesystem/filesystem.toml"` || f.DataType != "string" {
+		t.Errorf("unexpected flag: %+v", f)
+	}
+	if f := flags[2]; f.FieldName != "gc-interval" || f.DefaultValue != "10 * time.Minute" || f.DataType != "time.Duration" {
+		t.Errorf("unexpected flag: %+v", f)
+	}
+	// the usage of flag.Func precedes the function
+	if f := flags[3]; f.FieldName != "tag" || f.DefaultValue != "" || f.Description != "A tag of the node, can be repeated" {
+		t.Errorf("unexpected flag: %+v", f)
+	}
+}
@@ -0,0 +1,104 @@
+package cato
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cs3org/cato/exporter/sample"
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+const genericsSource = `package generics
+
+type Config struct {
+	Workers Pool[string]           ` + "`json:\"workers\" docs:\"{};The pool of workers\"`" + `
+	Limits  *Pair[string, float64] ` + "`json:\"limits\" docs:\"nil;The limits\"`" + `
+	Spares  Pool[string]           ` + "`json:\"spares\" docs:\"{};The pool of spare workers\"`" + `
+}
+
+// Pool is a generic pool of items.
+type Pool[T any] struct {
+	Items []T ` + "`json:\"items\" docs:\"[];The items of the pool\"`" + `
+	Size  int ` + "`json:\"size\" docs:\"10;The size of the pool\"`" + `
+}
+
+type Pair[K comparable, V any] struct {
+	Values map[K]V     ` + "`json:\"values\" docs:\"{};The values by key\"`" + `
+	Nested []Pool[V]   ` + "`json:\"nested\" docs:\"[];The nested pools\"`" + `
+}
+`
+
+func TestGenerics(t *testing.T) {
+	rootPath := writeSource(t, "generics.go", genericsSource)
+
+	configs, err := GenerateDocumentation(rootPath, &resources.CatoConfig{Driver: "jsonschema"})
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	file := configs[filepath.Join(rootPath, "generics.go")]
+
+	if title := resources.Section("Pair", file["Pair"]).Title(); title != "struct: Pair[K, V]" {
+		t.Errorf("unexpected title of Pair: %s", title)
+	}
+	if dt := file["Pool"][0].DataType; dt != "[]T" {
+		t.Errorf("expected the generic struct to be documented with its type parameters, got %s", dt)
+	}
+
+	if ref := utils.StructRef("*Pair[string, float64]", file); ref != "Pair[string, float64]" {
+		t.Fatalf("unexpected struct referred to by Limits: %q", ref)
+	}
+	types := []string{}
+	for _, f := range utils.Fields(file, "Pair[string, float64]") {
+		types = append(types, f.DataType)
+	}
+	if expected := []string{"map[string]float64", "[]Pool[float64]"}; !reflect.DeepEqual(types, expected) {
+		t.Errorf("expected the type arguments to be substituted, got %v", types)
+	}
+	if file["Pair"][0].DataType != "map[K]V" {
+		t.Errorf("expected the generic fields to be left unchanged, got %s", file["Pair"][0].DataType)
+	}
+
+	if roots := utils.Roots(file); !reflect.DeepEqual(roots, []string{"Config"}) {
+		t.Errorf("expected the generic structs not to be roots, got %v", roots)
+	}
+
+	nodes := sample.Build(file)
+	if len(nodes) != 3 || len(nodes[0].Children) != 2 || nodes[0].Children[0].Field.DataType != "[]string" {
+		t.Errorf("expected the instantiated pool to be nested in the sample, got %+v", nodes)
+	}
+	nested := nodes[1].Children[1]
+	if !nested.List || len(nested.Children) != 2 || nested.Children[0].Field.DataType != "[]float64" {
+		t.Errorf("expected the pools nested in Pair to be instantiated, got %+v", nested)
+	}
+
+	content, err := os.ReadFile(filepath.Join(rootPath, "Config.schema.json"))
+	if err != nil {
+		t.Fatalf("error reading the schema: %v", err)
+	}
+	var schema struct {
+		Properties map[string]map[string]interface{}
+		Defs       map[string]struct {
+			Properties map[string]map[string]interface{}
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(content, &schema); err != nil {
+		t.Fatalf("error decoding the schema: %v", err)
+	}
+	// the pool shared by two fields is defined once, with its items as strings
+	pool, ok := schema.Defs["Pool[string]"]
+	if !ok || !reflect.DeepEqual(pool.Properties["items"]["items"], map[string]interface{}{"type": "string"}) {
+		t.Errorf("expected Pool[string] to be defined with string items, got %+v", schema.Defs)
+	}
+	if ref := schema.Properties["workers"]["$ref"]; ref != "#/$defs/Pool%5Bstring%5D" {
+		t.Errorf("expected workers to refer to Pool[string], got %v", ref)
+	}
+	limits, _ := schema.Properties["limits"]["properties"].(map[string]interface{})
+	values, _ := limits["values"].(map[string]interface{})
+	if !reflect.DeepEqual(values["additionalProperties"], map[string]interface{}{"type": "number"}) {
+		t.Errorf("expected the values of Pair[string, float64] to be numbers, got %v", schema.Properties["limits"])
+	}
+}
@@ -0,0 +1,43 @@
+package cato
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+func TestGroups(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	fsConfigs := configs["examples/filesystem.go"]
+
+	section := resources.Section("UploadConfig", fsConfigs["UploadConfig"])
+	if section.Group != "Services" {
+		t.Errorf("expected UploadConfig to be in the Services group, got %q", section.Group)
+	}
+	if s := utils.SortedSections(fsConfigs); !reflect.DeepEqual(s, []string{"FileSystem", "UploadConfig"}) {
+		t.Errorf("unexpected order of the sections: %v", s)
+	}
+
+	names := []string{}
+	for _, f := range utils.SortedFields(fsConfigs["FileSystem"]) {
+		names = append(names, f.FieldName)
+	}
+	expected := []string{"CacheDirectory", "AvailableChecksums", "DriverConfig", "Uploads", "DumpRequests", "EnableLogging", "LogLevel", "LogFormat"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("unexpected order of the fields of FileSystem: %v", names)
+	}
+
+	// http_prefix is the lightest field
+	if f := utils.SortedFields(fsConfigs["UploadConfig"])[0]; f.FieldName != "http_prefix" || f.Weight != -1 {
+		t.Errorf("expected http_prefix to be documented first, got %s", f.FieldName)
+	}
+}
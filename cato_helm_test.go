@@ -0,0 +1,127 @@
+package cato
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cs3org/cato/resources"
+	"gopkg.in/yaml.v3"
+)
+
+func TestHelm(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		Driver: "helm",
+		DriverConfig: map[string]map[string]interface{}{
+			"helm": map[string]interface{}{
+				"ConfigMap": true,
+			},
+		},
+	}
+
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	values, err := os.ReadFile("examples/values.yaml")
+	if err != nil {
+		t.Fatalf("error reading the values: %v", err)
+	}
+	s := &sampleConfig{}
+	if err := yaml.Unmarshal(values, s); err != nil {
+		t.Fatalf("error decoding the values: %v", err)
+	}
+	checkSample(t, s)
+	// the descriptions are picked up by helm-docs
+	if !strings.Contains(string(values), "# -- Path of cache directory\n") {
+		t.Errorf("expected the values to be documented for helm-docs, got\n%s", values)
+	}
+	// the keys whose default is unknown are commented out, and their
+	// descriptions hidden from helm-docs
+	if !strings.Contains(string(values), "  # The path of the TLS certificate.\n  # Constraints: required when insecure is false\n  # cert_file:\n") || strings.Contains(string(values), "null") {
+		t.Errorf("expected the keys without default to be commented out, got\n%s", values)
+	}
+
+	content, err := os.ReadFile("examples/configmap.yaml")
+	if err != nil {
+		t.Fatalf("error reading the ConfigMap: %v", err)
+	}
+	var configMap struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string
+		Metadata   struct{ Name string }
+		Data       map[string]string
+	}
+	if err := yaml.Unmarshal(content, &configMap); err != nil {
+		t.Fatalf("error decoding the ConfigMap: %v", err)
+	}
+	if configMap.APIVersion != "v1" || configMap.Kind != "ConfigMap" || configMap.Metadata.Name != "config" {
+		t.Errorf("unexpected ConfigMap: %+v", configMap)
+	}
+	config := map[string]interface{}{}
+	if _, err := toml.Decode(configMap.Data["config.toml"], &config); err != nil {
+		t.Fatalf("error decoding the config of the ConfigMap: %v", err)
+	}
+	uploads, _ := config["Uploads"].(map[string]interface{})
+	if config["CacheDirectory"] != "/var/tmp/" || uploads["http_prefix"] != "uploads" {
+		t.Errorf("unexpected config in the ConfigMap: %v", config)
+	}
+}
+
+const helmNamesSource = `package pkg
+
+type Config struct {
+	Address string ` + "`docs:\"localhost\"`" + `
+}
+`
+
+func TestHelmConfigMapNames(t *testing.T) {
+	expected := map[string]string{
+		"_Storage.Provider_":           "storage-provider",
+		"__":                           "config",
+		strings.Repeat("a", 62) + "_b": strings.Repeat("a", 62),
+		strings.Repeat("b", 70):        strings.Repeat("b", 63),
+	}
+	files := map[string]string{}
+	for dir := range expected {
+		files[filepath.Join(dir, "config.go")] = helmNamesSource
+	}
+	rootPath := writeSources(t, files)
+
+	conf := &resources.CatoConfig{
+		Driver: "helm",
+		DriverConfig: map[string]map[string]interface{}{
+			"helm": map[string]interface{}{
+				"ConfigMap": true,
+			},
+		},
+	}
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	// the names are DNS labels
+	for dir, name := range expected {
+		content, err := os.ReadFile(filepath.Join(rootPath, dir, "configmap.yaml"))
+		if err != nil {
+			t.Fatalf("error reading the ConfigMap: %v", err)
+		}
+		var configMap struct {
+			Metadata struct{ Name string }
+			Data     map[string]string
+		}
+		if err := yaml.Unmarshal(content, &configMap); err != nil {
+			t.Fatalf("error decoding the ConfigMap: %v", err)
+		}
+		if configMap.Metadata.Name != name {
+			t.Errorf("expected the ConfigMap of %s to be named %s, got %s", dir, name, configMap.Metadata.Name)
+		}
+		if _, ok := configMap.Data[name+".toml"]; !ok {
+			t.Errorf("expected the config of %s to be embedded as %s.toml, got %v", dir, name, configMap.Data)
+		}
+	}
+}
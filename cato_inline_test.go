@@ -0,0 +1,115 @@
+package cato
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cs3org/cato/exporter/sample"
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+const inlineSource = `package inline
+
+type Config struct {
+	// The options of the cache
+	Cache struct {
+		// The number of cached entries
+		Size int ` + "`json:\"size\" env:\"SIZE\" docs:\"1024\"`" + `
+		Eviction struct {
+			Policy string ` + "`json:\"policy\" docs:\"lru\"`" + `
+		} ` + "`json:\"eviction\" docs:\"{}\"`" + `
+	} ` + "`json:\"cache\" env:\"CACHE\" docs:\"{}\"`" + `
+	Backends []struct {
+		Address string ` + "`json:\"address\" docs:\"localhost\"`" + `
+	} ` + "`json:\"backends\" docs:\"[]\"`" + `
+	Ignored struct {
+		Name string ` + "`docs:\"ignored\"`" + `
+	}
+}
+`
+
+func TestInlineStructs(t *testing.T) {
+	rootPath := writeSource(t, "inline.go", inlineSource)
+	file := filepath.Join(rootPath, "inline.go")
+
+	conf := &resources.CatoConfig{Driver: "markdown"}
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	sections := configs[file]
+
+	fields := sections["Config"]
+	if len(fields) != 2 || fields[0].DataType != "Config.Cache" || fields[1].DataType != "[]Config.Backends" {
+		t.Fatalf("unexpected fields of Config: %+v", fields)
+	}
+	if _, ok := sections["Config.Ignored"]; ok {
+		t.Errorf("expected the inline struct of an undocumented field to be left out")
+	}
+
+	size := sections["Config.Cache"][0]
+	if size.LineNumber != 7 || size.EnvName != "CACHE_SIZE" || size.Description != "The number of cached entries" {
+		t.Errorf("unexpected size field: %+v", size)
+	}
+	if ref := utils.StructRef(sections["Config.Cache"][1].DataType, sections); ref != "Config.Cache.Eviction" {
+		t.Errorf("expected the nested inline struct to be referenced, got %q", ref)
+	}
+
+	nodes := sample.Build(sections)
+	if len(nodes) != 2 || nodes[0].Key != "cache" || nodes[0].Children[1].Children[0].Key != "policy" || !nodes[1].List {
+		t.Errorf("expected the inline structs to be nested in the sample, got %+v", nodes)
+	}
+
+	doc, err := os.ReadFile(filepath.Join(rootPath, "inline.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(doc), "## struct: Config.Cache.Eviction") {
+		t.Errorf("expected the inline structs to be documented as sections, got\n%s", doc)
+	}
+
+	conf.Driver = "jsonschema"
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(rootPath, "Config.schema.json"))
+	if err != nil {
+		t.Fatalf("error reading the schema: %v", err)
+	}
+	var schema struct {
+		Properties struct {
+			Cache struct {
+				Properties struct {
+					Size     struct{ Type string }
+					Eviction struct {
+						Properties struct {
+							Policy struct{ Default string }
+						}
+					}
+				}
+			}
+			Backends struct {
+				Type  string
+				Items struct {
+					Properties struct {
+						Address struct{ Default string }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(content, &schema); err != nil {
+		t.Fatalf("error decoding the schema: %v", err)
+	}
+	cache, backends := schema.Properties.Cache, schema.Properties.Backends
+	if cache.Properties.Size.Type != "integer" || cache.Properties.Eviction.Properties.Policy.Default != "lru" {
+		t.Errorf("expected the inline structs to be nested in the schema, got\n%s", content)
+	}
+	if backends.Type != "array" || backends.Items.Properties.Address.Default != "localhost" {
+		t.Errorf("expected the list of inline structs to be nested in the schema, got\n%s", content)
+	}
+}
@@ -0,0 +1,42 @@
+package cato
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+func TestJSON(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		Driver: "json",
+	}
+
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	content, err := os.ReadFile("examples/example.json")
+	if err != nil {
+		t.Fatalf("error reading the sample: %v", err)
+	}
+	s := &sampleConfig{}
+	if err := json.Unmarshal(content, s); err != nil {
+		t.Fatalf("error decoding the sample: %v", err)
+	}
+	checkSample(t, s)
+
+	// the keys whose default is unknown are left out
+	var sample struct{ Uploads map[string]interface{} }
+	if err := json.Unmarshal(content, &sample); err != nil {
+		t.Fatalf("error decoding the sample: %v", err)
+	}
+	for _, k := range []string{"allowed_users", "jwt_secret", "cert_file"} {
+		if v, ok := sample.Uploads[k]; ok {
+			t.Errorf("expected %s to be left out of the sample, got %v", k, v)
+		}
+	}
+}
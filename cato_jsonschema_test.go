@@ -0,0 +1,118 @@
+package cato
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+const schemaDefsSource = `package defs
+
+type Server struct {
+	Primary Endpoint ` + "`json:\"primary\" docs:\"{};The primary endpoint\"`" + `
+	Backup  Endpoint ` + "`json:\"backup\" docs:\"{};The backup endpoint\"`" + `
+}
+
+type Endpoint struct {
+	Address string ` + "`json:\"address\" docs:\"localhost;The address\"`" + `
+}
+`
+
+// jsonSchema is the part of a generated schema checked by the tests.
+type jsonSchema struct {
+	Title      string
+	Type       string
+	Default    interface{}
+	Ref        string `json:"$ref"`
+	Required   []string
+	Properties map[string]*jsonSchema
+	Items      *jsonSchema
+	Defs       map[string]*jsonSchema `json:"$defs"`
+}
+
+func readSchema(t *testing.T, p string) *jsonSchema {
+	t.Helper()
+	content, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatalf("error reading the schema: %v", err)
+	}
+	s := &jsonSchema{}
+	if err := json.Unmarshal(content, s); err != nil {
+		t.Fatalf("error decoding the schema: %v", err)
+	}
+	return s
+}
+
+func TestJSONSchema(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		Driver: "jsonschema",
+	}
+
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	s := readSchema(t, "examples/FileSystem.schema.json")
+	if s.Title != "FileSystem" || s.Type != "object" {
+		t.Errorf("unexpected schema: %+v", s)
+	}
+	for _, key := range []string{"CacheDirectory", "EnableLogging", "AvailableChecksums", "DriverConfig", "Uploads"} {
+		if s.Properties[key] == nil {
+			t.Errorf("expected %s to be a property of the schema", key)
+		}
+	}
+	if d := s.Properties["CacheDirectory"].Default; d != "/var/tmp/" {
+		t.Errorf("unexpected default of CacheDirectory: %v", d)
+	}
+	if d := s.Properties["EnableLogging"].Default; d != false {
+		t.Errorf("unexpected default of EnableLogging: %v", d)
+	}
+	if d := s.Properties["AvailableChecksums"].Default; !reflect.DeepEqual(d, []interface{}{"adler", "rabin"}) {
+		t.Errorf("unexpected default of AvailableChecksums: %v", d)
+	}
+	if items := s.Properties["AvailableChecksums"].Items; items == nil || items.Type != "string" {
+		t.Errorf("expected AvailableChecksums to be a list of strings, got %+v", items)
+	}
+
+	// the struct used by a single field is nested in place
+	uploads := s.Properties["Uploads"]
+	if uploads == nil || uploads.Type != "object" || len(s.Defs) != 0 {
+		t.Fatalf("expected Uploads to be nested, got %+v", uploads)
+	}
+	if p := uploads.Properties["http_prefix"]; p == nil || p.Type != "string" || p.Default != "uploads" {
+		t.Errorf("unexpected http_prefix property: %+v", p)
+	}
+	// the constraints of the validate tag are mapped to JSON Schema
+	if !reflect.DeepEqual(uploads.Required, []string{"max_file_size"}) {
+		t.Errorf("expected max_file_size to be required, got %v", uploads.Required)
+	}
+	if p := uploads.Properties["max_file_size"]; p == nil || p.Default != float64(1048576) {
+		t.Errorf("expected the default of max_file_size to be read from its default tag, got %+v", p)
+	}
+}
+
+func TestJSONSchemaDefs(t *testing.T) {
+	rootPath := writeSource(t, "defs.go", schemaDefsSource)
+
+	if _, err := GenerateDocumentation(rootPath, &resources.CatoConfig{Driver: "jsonschema"}); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	// the struct used by several fields is defined once and referenced
+	s := readSchema(t, filepath.Join(rootPath, "Server.schema.json"))
+	endpoint := s.Defs["Endpoint"]
+	if endpoint == nil || endpoint.Properties["address"] == nil || endpoint.Properties["address"].Default != "localhost" {
+		t.Fatalf("expected Endpoint to be defined, got %+v", s.Defs)
+	}
+	for _, key := range []string{"primary", "backup"} {
+		if p := s.Properties[key]; p == nil || p.Ref != "#/$defs/Endpoint" {
+			t.Errorf("expected %s to refer to Endpoint, got %+v", key, p)
+		}
+	}
+}
@@ -19,6 +19,6 @@ func TestMarkdown(t *testing.T) {
 	}
 
 	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
-		t.Errorf("GenerateDocumentation(): %w", err)
+		t.Errorf("GenerateDocumentation(): %v", err)
 	}
 }
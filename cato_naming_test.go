@@ -0,0 +1,97 @@
+package cato
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+const namingSource = `package naming
+
+type Config struct {
+	GRPCAddress string ` + "`docs:\"0.0.0.0:9142\"`" + `
+	DataDir     string ` + "`json:\"data_dir\" mapstructure:\"datadir\" docs:\"/var/tmp\"`" + `
+	Skipped     string ` + "`json:\"-\" yaml:\"skipped_key\" docs:\"\"`" + `
+}
+`
+
+func TestNaming(t *testing.T) {
+	rootPath := writeSource(t, "naming.go", namingSource)
+
+	tests := []struct {
+		policy     string
+		precedence []string
+		names      []string
+	}{
+		{"", nil, []string{"GRPCAddress", "data_dir", "skipped_key"}},
+		{"snake_case", []string{"mapstructure", "json"}, []string{"grpc_address", "datadir", "skipped"}},
+		{"kebab-case", nil, []string{"grpc-address", "data_dir", "skipped_key"}},
+		{"lowerCamel", nil, []string{"grpcAddress", "data_dir", "skipped_key"}},
+	}
+
+	for _, tt := range tests {
+		diagnostics := []*resources.Diagnostic{}
+		conf := &resources.CatoConfig{
+			NamingPolicy:     tt.policy,
+			TagPrecedence:    tt.precedence,
+			ReportDiagnostic: func(d *resources.Diagnostic) { diagnostics = append(diagnostics, d) },
+		}
+		configs, err := GenerateDocumentation(rootPath, conf)
+		if err != nil {
+			t.Fatalf("GenerateDocumentation(): %v", err)
+		}
+
+		fields := configs[filepath.Join(rootPath, "naming.go")]["Config"]
+		for i, f := range fields {
+			if f.FieldName != tt.names[i] {
+				t.Errorf("policy %q: expected field %d to be named %s, got %s", tt.policy, i, tt.names[i], f.FieldName)
+			}
+		}
+		// the json and mapstructure tags of DataDir disagree
+		if len(diagnostics) != 1 || diagnostics[0].Line != 5 {
+			t.Errorf("policy %q: expected the names of DataDir to be reported, got %v", tt.policy, diagnostics)
+		}
+	}
+
+	if _, err := GenerateDocumentation(rootPath, &resources.CatoConfig{NamingPolicy: "PascalCase"}); err == nil {
+		t.Errorf("expected an error for an unknown naming policy")
+	}
+}
+
+const embeddedSource = `package embedded
+
+type Base struct {
+	Address string ` + "`docs:\"localhost:9142\"`" + `
+}
+
+type Config struct {
+	Base ` + "`json:\"base\" docs:\"&Base{}\"`" + `
+	*Extra ` + "`docs:\"&Extra{}\"`" + `
+}
+
+type Extra struct {
+	Token string ` + "`docs:\"\"`" + `
+}
+`
+
+func TestEmbeddedFields(t *testing.T) {
+	rootPath := writeSource(t, "embedded.go", embeddedSource)
+
+	configs, err := GenerateDocumentation(rootPath, &resources.CatoConfig{})
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	// embedded fields are named by their tags, or else by their type
+	fields := configs[filepath.Join(rootPath, "embedded.go")]["Config"]
+	names := []string{"base", "Extra"}
+	if len(fields) != len(names) {
+		t.Fatalf("expected %d fields, got %d", len(names), len(fields))
+	}
+	for i, f := range fields {
+		if f.FieldName != names[i] {
+			t.Errorf("expected field %d to be named %s, got %s", i, names[i], f.FieldName)
+		}
+	}
+}
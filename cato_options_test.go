@@ -0,0 +1,39 @@
+package cato
+
+import (
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+func TestOptions(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		Driver:      "markdown",
+		OptionTypes: []string{"Option"},
+		DriverConfig: map[string]map[string]interface{}{
+			"markdown": map[string]interface{}{
+				"ReferenceBase": "https://github.com/cs3org/cato/tree/master/examples",
+			},
+		},
+	}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	options := configs["examples/options.go"][resources.OptionsSectionName]
+	if len(options) != 3 {
+		t.Fatalf("expected 3 options, got %d", len(options))
+	}
+	if o := options[2]; o.FieldName != "WithUploadTimeout" || o.DataType != "func(d time.Duration) Option" || o.Description != "WithUploadTimeout sets the timeout of the uploads." {
+		t.Errorf("unexpected option: %+v", o)
+	}
+	// declarations starting a line are reported on their own line, not the
+	// one before
+	if o := options[0]; o.LineNumber != 9 {
+		t.Errorf("expected WithCacheDirectory to be declared at line 9, got %d", o.LineNumber)
+	}
+}
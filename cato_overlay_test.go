@@ -0,0 +1,98 @@
+package cato
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+const overlayServerSource = `package server
+
+// Config is the config of a server we can't annotate.
+type Config struct {
+	Addr    string
+	Timeout int
+	TLS     bool
+	Key     string
+	debug   bool
+}
+`
+
+const overlayAppSource = `package app
+
+type Config struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+
+var overlayFiles = map[string]string{
+	"go.mod": "module example.com/app\n",
+	"app.go": overlayAppSource,
+	"vendor/github.com/acme/server/server.go": overlayServerSource,
+	"overlays/server.yaml": `github.com/acme/server.Config.Addr:
+  name: address
+  default: localhost:8080
+  description: The address to listen on.
+  attributes:
+    group: Networking
+github.com/acme/server.Config.Timeout:
+  default: 30
+  attributes:
+    unit: seconds
+github.com/acme/server.Config.Removed:
+  description: A field which doesn't exist anymore.
+`,
+	"overlays/server.toml": `["github.com/acme/server.Config.Key"]
+description = "The TLS key."
+[ "github.com/acme/server.Config.Key".attributes ]
+sensitive = true
+`,
+	"overlays/app.json": `{
+  "example.com/app.Config.Name": {"attributes": {"since": "1.1"}},
+  "example.com/app.Missing": {}
+}
+`,
+}
+
+func TestOverlays(t *testing.T) {
+	rootPath := writeSources(t, overlayFiles)
+
+	diagnostics := []*resources.Diagnostic{}
+	conf := &resources.CatoConfig{
+		Overlays:         []string{"overlays/server.yaml", "overlays/server.toml", "overlays/app.json"},
+		ReportDiagnostic: func(d *resources.Diagnostic) { diagnostics = append(diagnostics, d) },
+	}
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	server := configs[filepath.Join(rootPath, "vendor/github.com/acme/server/server.go")]["Config"]
+	if len(server) != 3 {
+		t.Fatalf("expected 3 fields documented by the overlays, got %+v", server)
+	}
+	addr, timeout, key := server[0], server[1], server[2]
+	if addr.FieldName != "address" || addr.DefaultValue != `"localhost:8080"` || addr.Description != "The address to listen on." || addr.Group != "Networking" {
+		t.Errorf("unexpected address field: %+v", addr)
+	}
+	if timeout.FieldName != "Timeout" || timeout.DefaultValue != "30" || timeout.Unit != "seconds" {
+		t.Errorf("unexpected timeout field: %+v", timeout)
+	}
+	if key.FieldName != "Key" || !key.Sensitive || key.Description != "The TLS key." {
+		t.Errorf("unexpected key field: %+v", key)
+	}
+
+	app := configs[filepath.Join(rootPath, "app.go")]["Config"]
+	if len(app) != 1 || app[0].Since != "1.1" {
+		t.Errorf("expected the overlay to be merged into the tagged field, got %+v", app)
+	}
+
+	reported := map[string]int{}
+	for _, d := range diagnostics {
+		reported[filepath.Base(d.File)] = d.Line
+	}
+	if len(diagnostics) != 2 || reported["server.yaml"] != 11 || reported["app.json"] != 3 {
+		t.Errorf("expected the stale entries to be reported, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,53 @@
+package cato
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+const refsSource = `package refs
+
+import "time"
+
+const (
+	DefaultAddress = "0.0.0.0:" + defaultPort
+	defaultPort    = "9142"
+)
+
+type Config struct {
+	Address string        ` + "`docs:\"default=DefaultAddress;The address to listen on\"`" + `
+	Timeout time.Duration ` + "`docs:\"default=2 * time.Minute\"`" + `
+	Buffer  int           ` + "`docs:\"default=1 << 10\"`" + `
+	Missing string        ` + "`docs:\"default=os.TempDir()\"`" + `
+}
+`
+
+func TestDefaultRefs(t *testing.T) {
+	rootPath := writeSource(t, "refs.go", refsSource)
+
+	diagnostics := []*resources.Diagnostic{}
+	conf := &resources.CatoConfig{
+		ReportDiagnostic: func(d *resources.Diagnostic) { diagnostics = append(diagnostics, d) },
+	}
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	fields := configs[filepath.Join(rootPath, "refs.go")]["Config"]
+	expected := []string{`"0.0.0.0:9142"`, "120000000000", "1024", "os.TempDir()"}
+	for i, f := range fields {
+		if f.DefaultValue != expected[i] {
+			t.Errorf("expected the default of %s to be %s, got %s", f.FieldName, expected[i], f.DefaultValue)
+		}
+	}
+	if fields[0].Description != "The address to listen on" {
+		t.Errorf("unexpected description of Address: %q", fields[0].Description)
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Line != 14 {
+		t.Errorf("expected the default of Missing to be reported, got %v", diagnostics)
+	}
+}
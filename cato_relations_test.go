@@ -0,0 +1,44 @@
+package cato
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+func TestRelations(t *testing.T) {
+
+	rootPath := "examples/"
+	diagnostics := []*resources.Diagnostic{}
+	conf := &resources.CatoConfig{
+		ReportDiagnostic: func(d *resources.Diagnostic) { diagnostics = append(diagnostics, d) },
+	}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diagnostics)
+	}
+
+	fields := map[string]*resources.FieldInfo{}
+	for _, f := range configs["examples/filesystem.go"]["UploadConfig"] {
+		fields[f.FieldName] = f
+	}
+
+	// conditions can refer to the go name of the fields
+	for _, name := range []string{"cert_file", "key_file"} {
+		c := fields[name].Constraints
+		if !c.Required || !reflect.DeepEqual(c.When, &resources.Condition{Field: "insecure", Value: "false"}) {
+			t.Errorf("unexpected constraints for %s: %+v", name, c)
+		}
+	}
+	if c := fields["allowed_users"].Constraints; c.Exclusive != "acl" || !reflect.DeepEqual(c.ExclusiveWith, []string{"allowed_groups"}) {
+		t.Errorf("unexpected constraints for allowed_users: %+v", c)
+	}
+	if s := fields["cert_file"].Constraints.String(); s != "required when insecure is false" {
+		t.Errorf("unexpected description of the constraints of cert_file: %s", s)
+	}
+}
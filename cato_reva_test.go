@@ -0,0 +1,130 @@
+package cato
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+const revaFlagsSource = `package main
+
+import "flag"
+
+var configFile = flag.String("config", "/etc/revad/revad.toml", "Path of the config file")
+`
+
+const revaOptionsSource = `package pkg
+
+import "time"
+
+type Option func(*Server)
+
+type Server struct{}
+
+// WithTimeout sets the timeout of the requests.
+func WithTimeout(d time.Duration) Option {
+	return func(*Server) {}
+}
+`
+
+const revaDefaultsSource = `package pkg
+
+type settings map[string]interface{}
+
+func (s settings) SetDefault(key string, value interface{}) {}
+
+type Config struct {
+	Address string ` + "`docs:\"localhost\"`" + `
+}
+
+func registerDefaults(s settings) {
+	s.SetDefault("uploads.chunk_size", 65536)
+}
+`
+
+const revaPlaceholdersSource = `package pkg
+
+import "time"
+
+type Config struct {
+	Wait  time.Duration ` + "`docs:\";How long to wait\"`" + `
+	Hosts []string      ` + "`docs:\";The hosts to reach\"`" + `
+}
+`
+
+// generateReva documents the sources with the reva driver and returns the
+// index written for the package pkg.
+func generateReva(t *testing.T, files map[string]string, conf *resources.CatoConfig) string {
+	t.Helper()
+	rootPath := writeSources(t, files)
+	conf.Driver = "reva"
+	conf.DriverConfig = map[string]map[string]interface{}{
+		"reva": map[string]interface{}{
+			"DocPaths": map[string]string{"": "docs"},
+		},
+	}
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(rootPath, "docs", "pkg", "_index.md"))
+	if err != nil {
+		t.Fatalf("error reading the docs: %v", err)
+	}
+	return string(content)
+}
+
+func TestRevaFlags(t *testing.T) {
+	docs := generateReva(t, map[string]string{"pkg/main.go": revaFlagsSource}, &resources.CatoConfig{DocumentFlags: true})
+
+	if !strings.Contains(docs, "{{% dir name=\"-config\" type=\"string\" %}}\nPath of the config file \nDefault: `\"/etc/revad/revad.toml\"`\n{{% /dir %}}") {
+		t.Errorf("unexpected docs of the config flag:\n%s", docs)
+	}
+	// flags aren't set in the config files
+	for _, s := range []string{"highlight toml", "default="} {
+		if strings.Contains(docs, s) {
+			t.Errorf("expected the docs of the flags not to contain %q:\n%s", s, docs)
+		}
+	}
+}
+
+func TestRevaOptions(t *testing.T) {
+	docs := generateReva(t, map[string]string{"pkg/options.go": revaOptionsSource}, &resources.CatoConfig{OptionTypes: []string{"Option"}})
+
+	if !strings.Contains(docs, "{{% dir name=\"WithTimeout\" type=\"func(d time.Duration) Option\" %}}\nWithTimeout sets the timeout of the requests. \n{{% /dir %}}") {
+		t.Errorf("unexpected docs of the WithTimeout option:\n%s", docs)
+	}
+	// an option name isn't a TOML key
+	for _, s := range []string{"highlight toml", "WithTimeout =", "default="} {
+		if strings.Contains(docs, s) {
+			t.Errorf("expected the docs of the options not to contain %q:\n%s", s, docs)
+		}
+	}
+}
+
+func TestRevaRegisteredDefaults(t *testing.T) {
+	docs := generateReva(t, map[string]string{"pkg/defaults.go": revaDefaultsSource}, &resources.CatoConfig{DefaultFuncs: []string{"SetDefault"}})
+
+	// the key path is split into the table and the key
+	if !strings.Contains(docs, "{{< highlight toml >}}\n[pkg.uploads]\nchunk_size = 65536\n{{< /highlight >}}") {
+		t.Errorf("unexpected docs of uploads.chunk_size:\n%s", docs)
+	}
+	if !strings.Contains(docs, "[pkg]\nAddress = \"localhost\"\n") {
+		t.Errorf("unexpected docs of Address:\n%s", docs)
+	}
+}
+
+func TestRevaPlaceholders(t *testing.T) {
+	docs := generateReva(t, map[string]string{"pkg/config.go": revaPlaceholdersSource}, &resources.CatoConfig{})
+
+	// the fields without default are commented out with a placeholder of
+	// their type
+	for _, s := range []string{"[pkg]\n# Wait = <time.Duration>\n", "[pkg]\n# Hosts = <[]string>\n"} {
+		if !strings.Contains(docs, s) {
+			t.Errorf("expected the docs to contain %q:\n%s", s, docs)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cato
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+const sensitiveSource = `package sensitive
+
+type Config struct {
+	Password  string ` + "`docs:\"secret;sensitive\"`" + `
+	APIKey    string ` + "`docs:\"dev-key\"`" + `
+	TokenTTL  int    ` + "`docs:\"3600;sensitive=false\"`" + `
+	Address   string ` + "`docs:\"localhost\"`" + `
+}
+`
+
+func TestSensitive(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		Driver:       "jsonschema",
+		DefaultFuncs: []string{"SetDefault"},
+	}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	// neither the docs nor the registered defaults of secrets are documented
+	for _, f := range configs["examples/filesystem.go"]["UploadConfig"] {
+		if f.FieldName == "jwt_secret" && (!f.Sensitive || f.DefaultValue != "") {
+			t.Errorf("expected jwt_secret to be sensitive and redacted, got %+v", f)
+		}
+	}
+
+	schema, err := os.ReadFile("examples/FileSystem.schema.json")
+	if err != nil {
+		t.Fatalf("error reading the schema: %v", err)
+	}
+	if strings.Contains(string(schema), "changeme") || !strings.Contains(string(schema), `"writeOnly": true`) {
+		t.Errorf("expected the secret to be redacted and write only in the schema")
+	}
+}
+
+func TestSecretNames(t *testing.T) {
+	rootPath := writeSource(t, "sensitive.go", sensitiveSource)
+
+	diagnostics := []*resources.Diagnostic{}
+	conf := &resources.CatoConfig{
+		ReportDiagnostic: func(d *resources.Diagnostic) { diagnostics = append(diagnostics, d) },
+	}
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	// TokenTTL is explicitly not a secret
+	if len(diagnostics) != 1 || diagnostics[0].Line != 5 {
+		t.Errorf("expected APIKey to be reported, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,31 @@
+package cato
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSource writes src to the file name of a temporary directory, removed
+// along with the test, and returns the directory.
+func writeSource(t *testing.T, name, src string) string {
+	t.Helper()
+	return writeSources(t, map[string]string{name: src})
+}
+
+// writeSources writes the files, by path relative to a temporary directory,
+// and returns the directory.
+func writeSources(t *testing.T, files map[string]string) string {
+	t.Helper()
+	rootPath := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(rootPath, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rootPath
+}
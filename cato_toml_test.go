@@ -0,0 +1,45 @@
+package cato
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cs3org/cato/resources"
+)
+
+func TestTOML(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		Driver: "toml",
+	}
+
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	sample := map[string]interface{}{}
+	if _, err := toml.DecodeFile("examples/example.toml", &sample); err != nil {
+		t.Fatalf("error decoding the sample: %v", err)
+	}
+	if sample["CacheDirectory"] != "/var/tmp/" || sample["EnableLogging"] != false {
+		t.Errorf("unexpected defaults in the sample: %v", sample)
+	}
+	if c := sample["AvailableChecksums"]; !reflect.DeepEqual(c, []interface{}{"adler", "rabin"}) {
+		t.Errorf("unexpected default of AvailableChecksums: %v", c)
+	}
+	driver, _ := sample["DriverConfig"].(map[string]interface{})
+	if d, _ := driver["json"].(map[string]interface{}); d["encoding"] != "UTF8" {
+		t.Errorf("unexpected default of DriverConfig: %v", sample["DriverConfig"])
+	}
+
+	// the nested structs are written as tables
+	uploads, ok := sample["Uploads"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected Uploads to be a table, got %v", sample["Uploads"])
+	}
+	if uploads["http_prefix"] != "uploads" || uploads["disable_tus"] != false {
+		t.Errorf("unexpected defaults of Uploads: %v", uploads)
+	}
+}
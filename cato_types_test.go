@@ -0,0 +1,129 @@
+package cato
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+func TestTypeNames(t *testing.T) {
+
+	rootPath := "examples/"
+	configs, err := GenerateDocumentation(rootPath, &resources.CatoConfig{})
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	fs := configs["examples/filesystem.go"]
+
+	expected := map[string]map[string]string{
+		"toml": {
+			"CacheDirectory":     "string",
+			"AvailableChecksums": "list of strings",
+			"DriverConfig":       "table of tables of values",
+			"Uploads":            "table",
+			"EnableLogging":      "boolean",
+			"LogFormat":          "string",
+			"max_file_size":      "integer",
+		},
+		"json": {
+			"AvailableChecksums": "array of strings",
+			"DriverConfig":       "object of objects of values",
+			"Uploads":            "object",
+		},
+	}
+	for format, names := range expected {
+		n, err := utils.NewTypeNamer(format, map[string]string{"[]string": "comma separated strings"})
+		if err != nil {
+			t.Fatalf("NewTypeNamer(%s): %v", format, err)
+		}
+		for _, fields := range fs {
+			for _, f := range fields {
+				name, ok := names[f.FieldName]
+				if !ok {
+					continue
+				}
+				if f.FieldName == "AvailableChecksums" {
+					name = "comma separated strings"
+				}
+				if got := n.TypeName(f, fs); got != name {
+					t.Errorf("expected %s to be named %q in %s, got %q", f.FieldName, name, format, got)
+				}
+			}
+		}
+	}
+
+	n, err := utils.NewTypeNamer("", nil)
+	if err != nil {
+		t.Fatalf("NewTypeNamer(): %v", err)
+	}
+	if f := fs["FileSystem"][2]; n.TypeName(f, fs) != f.DataType {
+		t.Errorf("expected the go type of %s to be kept, got %q", f.FieldName, n.TypeName(f, fs))
+	}
+
+	if _, err := utils.NewTypeNamer("ini", nil); err == nil {
+		t.Error("expected the ini format to be rejected")
+	}
+}
+
+func TestCompositeTypeNames(t *testing.T) {
+	n, err := utils.NewTypeNamer("yaml", nil)
+	if err != nil {
+		t.Fatalf("NewTypeNamer(): %v", err)
+	}
+	for dataType, name := range map[string]string{
+		"time.Duration":       "string (duration)",
+		"[]time.Duration":     "list of strings (duration)",
+		"map[string]*float64": "map of numbers",
+		"[]interface{}":       "list of values",
+		"map[string][]byte":   "map of strings (base64)",
+		"pkg.Unknown":         "pkg.Unknown",
+	} {
+		if got := n.TypeName(&resources.FieldInfo{DataType: dataType}, nil); got != name {
+			t.Errorf("expected %s to be named %q, got %q", dataType, name, got)
+		}
+	}
+}
+
+const typesSource = `package types
+
+type Config struct {
+	Hosts   []string          ` + "`docs:\"[localhost]\"`" + `
+	Labels  map[string]string ` + "`docs:\"{}\"`" + `
+	Retries int               ` + "`docs:\"3\"`" + `
+}
+`
+
+func TestDriverTypeNames(t *testing.T) {
+	rootPath := writeSource(t, "types.go", typesSource)
+	conf := &resources.CatoConfig{
+		Driver: "html",
+		DriverConfig: map[string]map[string]interface{}{
+			"html": map[string]interface{}{
+				"TypeFormat": "toml",
+				"TypeNames":  map[string]string{"map[string]string": "labels"},
+			},
+		},
+	}
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(rootPath, "types.html"))
+	if err != nil {
+		t.Fatalf("error reading the docs: %v", err)
+	}
+	// the go types are kept as the titles of the user-facing names
+	for _, s := range []string{
+		`<span title="[]string">list of strings</span>`,
+		`<span title="map[string]string">labels</span>`,
+		`<span title="int">integer</span>`,
+	} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("expected the docs to contain %q:\n%s", s, content)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package cato
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+const unitsSource = `package units
+
+import "time"
+
+type Config struct {
+	Timeout  time.Duration ` + "`docs:\"default=30 * time.Second\"`" + `
+	Interval int           ` + "`docs:\"90;unit=seconds\"`" + `
+	Cache    int64         ` + "`docs:\"default=64 << 20;unit=bytes\"`" + `
+	Chunk    int64         ` + "`docs:\"1536;unit=bytes\"`" + `
+	Small    int64         ` + "`docs:\"512;unit=bytes\"`" + `
+	Name     string        ` + "`docs:\"cato;unit=bytes\"`" + `
+}
+`
+
+func TestUnits(t *testing.T) {
+	rootPath := writeSource(t, "units.go", unitsSource)
+
+	configs, err := GenerateDocumentation(rootPath, &resources.CatoConfig{})
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	fields := configs[filepath.Join(rootPath, "units.go")]["Config"]
+	expected := []struct{ unit, raw, human string }{
+		{"nanoseconds", "30000000000", "30s"},
+		{"seconds", "90", "1m30s"},
+		{"bytes", "67108864", "64 MiB"},
+		{"bytes", "1536", "1.5 KiB"},
+		{"bytes", "512", "512 B"},
+		{"bytes", `"cato"`, ""},
+	}
+	for i, f := range fields {
+		if u := utils.Unit(f); u != expected[i].unit {
+			t.Errorf("expected the unit of %s to be %s, got %s", f.FieldName, expected[i].unit, u)
+		}
+		if f.DefaultValue != expected[i].raw {
+			t.Errorf("expected the default of %s to be %s, got %s", f.FieldName, expected[i].raw, f.DefaultValue)
+		}
+		if h := utils.HumanDefault(f); h != expected[i].human {
+			t.Errorf("expected %s to be rendered as %q, got %q", f.FieldName, expected[i].human, h)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cato
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+const untaggedSource = `package untagged
+
+// Server is documented through the config.
+type Server struct {
+	Address string ` + "`json:\"address\"`" + ` // The address to listen on
+	Workers int    ` + "`default:\"4\"`" + `
+	// The timeout of the requests
+	Timeout int    ` + "`docs:\"30;unit=seconds\"`" + `
+	Debug   bool   ` + "`docs:\"-\"`" + `
+	secret  string
+	Logger
+}
+
+// Client is documented through its directive.
+//
+//cato:untagged
+type Client struct {
+	Endpoint string
+}
+
+type Other struct {
+	Name  string // The name is documented
+	Skipped string
+}
+
+type Logger struct{}
+`
+
+func TestUntaggedStructs(t *testing.T) {
+	rootPath := writeSource(t, "untagged.go", untaggedSource)
+
+	conf := &resources.CatoConfig{
+		UntaggedStructs: []string{"Server"},
+	}
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	file := configs[filepath.Join(rootPath, "untagged.go")]
+
+	server := file["Server"]
+	expected := []struct {
+		name, def, desc string
+		unknown         bool
+	}{
+		{"address", "", "The address to listen on", true},
+		{"Workers", "4", "", false},
+		{"Timeout", "30", "The timeout of the requests", false},
+	}
+	if len(server) != len(expected) {
+		t.Fatalf("expected %d fields in Server, got %d", len(expected), len(server))
+	}
+	for i, f := range server {
+		e := expected[i]
+		if f.FieldName != e.name || f.DefaultValue != e.def || f.Description != e.desc || f.DefaultUnknown != e.unknown {
+			t.Errorf("unexpected field %d of Server: %+v", i, f)
+		}
+	}
+
+	if client := file["Client"]; len(client) != 1 || client[0].FieldName != "Endpoint" || !client[0].DefaultUnknown {
+		t.Errorf("expected Endpoint to be documented through the directive, got %+v", client)
+	}
+	if _, ok := file["Other"]; ok {
+		t.Errorf("expected Other not to be documented, got %+v", file["Other"])
+	}
+}
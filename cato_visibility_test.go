@@ -0,0 +1,39 @@
+package cato
+
+import (
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+)
+
+func TestVisibility(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{}
+
+	configs, err := GenerateDocumentation(rootPath, conf)
+	if err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+	fsConfigs := configs["examples/filesystem.go"]
+
+	levels := map[string]string{}
+	for _, fields := range fsConfigs {
+		for _, f := range fields {
+			levels[f.FieldName] = f.Visibility
+		}
+	}
+	if levels["DumpRequests"] != resources.VisibilityInternal || levels["workers"] != resources.VisibilityAdvanced || levels["CacheDirectory"] != "" {
+		t.Errorf("unexpected visibility levels: %v", levels)
+	}
+
+	basic := filterVisibility(fsConfigs, []string{resources.VisibilityBasic})
+	if len(basic["FileSystem"]) != len(fsConfigs["FileSystem"])-1 || len(basic["UploadConfig"]) != len(fsConfigs["UploadConfig"])-1 {
+		t.Errorf("expected DumpRequests and workers to be filtered out")
+	}
+
+	// UploadConfig is only referred to by a basic field
+	if advanced := filterVisibility(fsConfigs, []string{resources.VisibilityAdvanced}); len(advanced) != 0 {
+		t.Errorf("expected no struct to be left, got %v", advanced)
+	}
+}
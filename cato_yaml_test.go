@@ -0,0 +1,62 @@
+package cato
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/cs3org/cato/resources"
+	"gopkg.in/yaml.v3"
+)
+
+// sampleConfig is the part of the sample of examples/filesystem.go checked by
+// the tests of the yaml and json drivers.
+type sampleConfig struct {
+	CacheDirectory     string                       `yaml:"CacheDirectory"`
+	EnableLogging      *bool                        `yaml:"EnableLogging"`
+	AvailableChecksums []string                     `yaml:"AvailableChecksums"`
+	DriverConfig       map[string]map[string]string `yaml:"DriverConfig"`
+	Uploads            struct {
+		DisableTus *bool  `yaml:"disable_tus" json:"disable_tus"`
+		HTTPPrefix string `yaml:"http_prefix" json:"http_prefix"`
+	} `yaml:"Uploads"`
+}
+
+func checkSample(t *testing.T, s *sampleConfig) {
+	t.Helper()
+	if s.CacheDirectory != "/var/tmp/" || s.EnableLogging == nil || *s.EnableLogging {
+		t.Errorf("unexpected defaults in the sample: %+v", s)
+	}
+	if !reflect.DeepEqual(s.AvailableChecksums, []string{"adler", "rabin"}) {
+		t.Errorf("unexpected default of AvailableChecksums: %v", s.AvailableChecksums)
+	}
+	if s.DriverConfig["json"]["encoding"] != "UTF8" || s.DriverConfig["xml"]["encoding"] != "ASCII" {
+		t.Errorf("unexpected default of DriverConfig: %v", s.DriverConfig)
+	}
+	// the nested structs are nested in the sample
+	if u := s.Uploads; u.HTTPPrefix != "uploads" || u.DisableTus == nil || *u.DisableTus {
+		t.Errorf("unexpected defaults of Uploads: %+v", u)
+	}
+}
+
+func TestYAML(t *testing.T) {
+
+	rootPath := "examples/"
+	conf := &resources.CatoConfig{
+		Driver: "yaml",
+	}
+
+	if _, err := GenerateDocumentation(rootPath, conf); err != nil {
+		t.Fatalf("GenerateDocumentation(): %v", err)
+	}
+
+	content, err := os.ReadFile("examples/example.yaml")
+	if err != nil {
+		t.Fatalf("error reading the sample: %v", err)
+	}
+	s := &sampleConfig{}
+	if err := yaml.Unmarshal(content, s); err != nil {
+		t.Fatalf("error decoding the sample: %v", err)
+	}
+	checkSample(t, s)
+}
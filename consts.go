@@ -0,0 +1,286 @@
+package cato
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/cs3org/cato/resources"
+)
+
+// timeConsts are the constants of the time package commonly used in defaults.
+var timeConsts = map[string]time.Duration{
+	"Nanosecond":  time.Nanosecond,
+	"Microsecond": time.Microsecond,
+	"Millisecond": time.Millisecond,
+	"Second":      time.Second,
+	"Minute":      time.Minute,
+	"Hour":        time.Hour,
+}
+
+// getPackageConsts indexes the constants of every package holding documented
+// files.
+func getPackageConsts(fileList []string, filesConfigs map[string]map[string][]*resources.FieldInfo) (map[string]*constIndex, error) {
+	dirs := map[string]bool{}
+	for file := range filesConfigs {
+		dirs[path.Dir(file)] = true
+	}
+
+	pkgs := map[string][]*ast.File{}
+	fset := token.NewFileSet()
+	for _, file := range fileList {
+		dir := path.Dir(file)
+		if !dirs[dir] {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		pkgs[dir] = append(pkgs[dir], f)
+	}
+
+	consts := map[string]*constIndex{}
+	for dir, files := range pkgs {
+		consts[dir] = newConstIndex(files)
+	}
+	return consts, nil
+}
+
+// constDecl is a constant declared at the package level.
+type constDecl struct {
+	name string
+	// typeName is the name of the type of the constant, if it's declared in
+	// the package
+	typeName string
+	expr     ast.Expr
+	iota     int
+	doc      string
+}
+
+// constIndex evaluates the constants declared at the package level, following
+// the references between them independently of the order of declaration.
+type constIndex struct {
+	decls      map[string]*constDecl
+	order      []*constDecl
+	types      map[string]bool
+	values     map[string]constant.Value
+	evaluating map[string]bool
+}
+
+func newConstIndex(files []*ast.File) *constIndex {
+	idx := &constIndex{
+		decls:      map[string]*constDecl{},
+		types:      map[string]bool{},
+		values:     map[string]constant.Value{},
+		evaluating: map[string]bool{},
+	}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch gd.Tok {
+			case token.TYPE:
+				for _, spec := range gd.Specs {
+					idx.types[spec.(*ast.TypeSpec).Name.Name] = true
+				}
+			case token.CONST:
+				idx.addConsts(gd)
+			}
+		}
+	}
+	return idx
+}
+
+func (idx *constIndex) addConsts(gd *ast.GenDecl) {
+	// specs without values repeat the type and values of the previous one
+	var typ ast.Expr
+	var values []ast.Expr
+	for i, spec := range gd.Specs {
+		vs := spec.(*ast.ValueSpec)
+		if vs.Type != nil || len(vs.Values) > 0 {
+			typ, values = vs.Type, vs.Values
+		}
+
+		doc := getDescription(vs.Doc)
+		if doc == "" {
+			doc = getDescription(vs.Comment)
+		}
+		if doc == "" && len(gd.Specs) == 1 {
+			doc = getDescription(gd.Doc)
+		}
+
+		for j, name := range vs.Names {
+			if name.Name == "_" || j >= len(values) {
+				continue
+			}
+			d := &constDecl{
+				name:     name.Name,
+				typeName: constTypeName(typ, values[j]),
+				expr:     values[j],
+				iota:     i,
+				doc:      doc,
+			}
+			idx.decls[d.name] = d
+			idx.order = append(idx.order, d)
+		}
+	}
+}
+
+// constTypeName returns the name of the declared type of a constant, or of the
+// type it's converted to, e.g. Mode for Mode("a").
+func constTypeName(typ, value ast.Expr) string {
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name
+	}
+	if call, ok := value.(*ast.CallExpr); ok && typ == nil && len(call.Args) == 1 {
+		if id, ok := call.Fun.(*ast.Ident); ok {
+			return id.Name
+		}
+	}
+	return ""
+}
+
+// value returns the value of the constant with the given name.
+func (idx *constIndex) value(name string) (constant.Value, bool) {
+	if v, ok := idx.values[name]; ok {
+		return v, true
+	}
+	d, ok := idx.decls[name]
+	if !ok || idx.evaluating[name] {
+		return nil, false
+	}
+	idx.evaluating[name] = true
+	defer delete(idx.evaluating, name)
+
+	v, ok := idx.eval(d.expr, d.iota)
+	if ok {
+		idx.values[name] = v
+	}
+	return v, ok
+}
+
+// eval evaluates a constant expression, iota being the index of the spec it
+// appears in.
+func (idx *constIndex) eval(expr ast.Expr, iota int) (constant.Value, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		v := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		return v, v.Kind() != constant.Unknown
+	case *ast.Ident:
+		switch e.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota)), true
+		case "true", "false":
+			return constant.MakeBool(e.Name == "true"), true
+		}
+		return idx.value(e.Name)
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && x.Name == "time" {
+			if d, ok := timeConsts[e.Sel.Name]; ok {
+				return constant.MakeInt64(int64(d)), true
+			}
+		}
+		return nil, false
+	case *ast.ParenExpr:
+		return idx.eval(e.X, iota)
+	case *ast.CallExpr:
+		// conversions to the named types of the package or to basic types
+		if len(e.Args) != 1 {
+			return nil, false
+		}
+		if id, ok := e.Fun.(*ast.Ident); !ok || id.Name == "len" {
+			return nil, false
+		}
+		return idx.eval(e.Args[0], iota)
+	case *ast.UnaryExpr:
+		x, ok := idx.eval(e.X, iota)
+		if !ok {
+			return nil, false
+		}
+		return constant.UnaryOp(e.Op, x, 0), true
+	case *ast.BinaryExpr:
+		x, ok := idx.eval(e.X, iota)
+		if !ok {
+			return nil, false
+		}
+		y, ok := idx.eval(e.Y, iota)
+		if !ok {
+			return nil, false
+		}
+		return binaryOp(x, e.Op, y)
+	}
+	return nil, false
+}
+
+func binaryOp(x constant.Value, op token.Token, y constant.Value) (v constant.Value, ok bool) {
+	// constant panics on operands of mismatched kinds
+	defer func() {
+		if recover() != nil {
+			v, ok = nil, false
+		}
+	}()
+
+	switch op {
+	case token.SHL, token.SHR:
+		s, exact := constant.Uint64Val(y)
+		if !exact {
+			return nil, false
+		}
+		return constant.Shift(x, op, uint(s)), true
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return constant.MakeBool(constant.Compare(x, op, y)), true
+	case token.QUO:
+		if x.Kind() == constant.Int && y.Kind() == constant.Int {
+			// integer division
+			op = token.QUO_ASSIGN
+		}
+	}
+	v = constant.BinaryOp(x, op, y)
+	return v, v.Kind() != constant.Unknown
+}
+
+// formatConst returns the go literal of a constant value.
+func formatConst(v constant.Value) string {
+	switch v.Kind() {
+	case constant.String:
+		return strconv.Quote(constant.StringVal(v))
+	case constant.Float:
+		return v.String()
+	}
+	return v.ExactString()
+}
+
+// resolveDefaultRefs evaluates the constant expressions the default values of
+// the fields refer to, e.g. DefaultAddress or 30 * time.Second. The references
+// which can't be resolved are reported and left as they are.
+func resolveDefaultRefs(filesConfigs map[string]map[string][]*resources.FieldInfo, consts map[string]*constIndex, conf *resources.CatoConfig) {
+	for file, configs := range filesConfigs {
+		idx := consts[path.Dir(file)]
+		for _, fields := range configs {
+			for _, f := range fields {
+				if f.DefaultRef == "" {
+					continue
+				}
+				expr, err := parser.ParseExpr(f.DefaultRef)
+				if err != nil {
+					report(conf, file, f.LineNumber, "invalid default value reference %s of %s: %v", f.DefaultRef, f.FieldName, err)
+					continue
+				}
+				v, ok := idx.eval(expr, 0)
+				if !ok {
+					report(conf, file, f.LineNumber, "can't resolve the default value %s of %s to a constant", f.DefaultRef, f.FieldName)
+					continue
+				}
+				f.DefaultValue = formatConst(v)
+			}
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package cato
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+func report(conf *resources.CatoConfig, file string, line int, format string, args ...interface{}) {
+	if conf.ReportDiagnostic == nil {
+		return
+	}
+	conf.ReportDiagnostic(&resources.Diagnostic{
+		File:    file,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// literalType returns the data type of a literal default value.
+func literalType(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			return "string"
+		case token.INT:
+			return "int"
+		case token.FLOAT:
+			return "float64"
+		}
+	case *ast.Ident:
+		if e.Name == "true" || e.Name == "false" {
+			return "bool"
+		}
+	}
+	return ""
+}
+
+func getDefaultsToDocument(fileTree *ast.File, funcs []string, fset *token.FileSet, lineNos []int) ([]*resources.FieldInfo, error) {
+	isDefaultFunc := map[string]bool{}
+	for _, f := range funcs {
+		isDefaultFunc[f] = true
+	}
+
+	section := &resources.SectionInfo{Name: resources.DefaultsSectionName, Kind: resources.DefaultsSection}
+	defaults := []*resources.FieldInfo{}
+	var err error
+
+	ast.Inspect(fileTree, func(node ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		var name string
+		switch fn := call.Fun.(type) {
+		case *ast.Ident:
+			name = fn.Name
+		case *ast.SelectorExpr:
+			name = fn.Sel.Name
+		}
+		if !isDefaultFunc[name] {
+			return true
+		}
+		// keys computed at runtime can't be matched
+		if lit, ok := call.Args[0].(*ast.BasicLit); !ok || lit.Kind != token.STRING {
+			return true
+		}
+
+		var key, defaultVal string
+		var isLit bool
+		if key, _, err = stringValue(call.Args[0], fset); err != nil {
+			return false
+		}
+		if defaultVal, isLit, err = stringValue(call.Args[1], fset); err != nil {
+			return false
+		}
+		if isLit {
+			defaultVal = fmt.Sprintf("\"%s\"", defaultVal)
+		}
+
+		var lineNumber int
+		if lineNumber, err = getLineNumber(lineNos, int(call.Pos())); err != nil {
+			return false
+		}
+
+		defaults = append(defaults, &resources.FieldInfo{
+			FieldName:    key,
+			DefaultValue: defaultVal,
+			DataType:     literalType(call.Args[1]),
+			LineNumber:   lineNumber,
+			Section:      section,
+		})
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decoding registered default: %w", err)
+	}
+
+	return defaults, nil
+}
+
+type keyPathField struct {
+	field *resources.FieldInfo
+	file  string
+}
+
+// keyPaths returns the fields of the structs of a package indexed by their
+// lower cased key path from the top-level structs, e.g. grpc.address.
+func keyPaths(pkgConfigs map[string][]*resources.FieldInfo, files map[*resources.FieldInfo]string) map[string]keyPathField {
+	paths := map[string]keyPathField{}
+
+	var walk func(s, prefix string, visiting map[string]bool)
+	walk = func(s, prefix string, visiting map[string]bool) {
+		visiting[s] = true
+		defer delete(visiting, s)
+		for _, f := range pkgConfigs[s] {
+			key := prefix + strings.ToLower(f.FieldName)
+			if ref := utils.StructRef(f.DataType, pkgConfigs); ref != "" {
+				if !visiting[ref] {
+					walk(ref, key+".", visiting)
+				}
+				continue
+			}
+			if _, ok := paths[key]; !ok {
+				paths[key] = keyPathField{field: f, file: files[f]}
+			}
+		}
+	}
+
+	for _, root := range utils.Roots(pkgConfigs) {
+		walk(root, "", map[string]bool{})
+	}
+	return paths
+}
+
+// mergeRegisteredDefaults matches the registered defaults of every package
+// with the fields of its structs by key path. The matched defaults are used for
+// the fields documented without one, and the keys which are only registered
+// are reported, as well as the ones which are only documented if
+// ReportMissingDefaults is set.
+func mergeRegisteredDefaults(filesConfigs map[string]map[string][]*resources.FieldInfo, conf *resources.CatoConfig) {
+	pkgFiles := map[string][]string{}
+	for file := range filesConfigs {
+		dir := path.Dir(file)
+		pkgFiles[dir] = append(pkgFiles[dir], file)
+	}
+
+	for _, files := range pkgFiles {
+		sort.Strings(files)
+		pkgConfigs := map[string][]*resources.FieldInfo{}
+		fieldFiles := map[*resources.FieldInfo]string{}
+		for _, file := range files {
+			for s, fields := range filesConfigs[file] {
+				if resources.Section(s, fields).Kind == resources.StructSection {
+					pkgConfigs[s] = fields
+				}
+				for _, f := range fields {
+					fieldFiles[f] = file
+				}
+			}
+		}
+
+		registered := false
+		matched := map[string]bool{}
+		paths := keyPaths(pkgConfigs, fieldFiles)
+
+		for _, file := range files {
+			defaults := filesConfigs[file][resources.DefaultsSectionName]
+			if len(defaults) == 0 {
+				continue
+			}
+			registered = true
+
+			unmatched := []*resources.FieldInfo{}
+			for _, d := range defaults {
+				key := strings.ToLower(d.FieldName)
+				p, ok := paths[key]
+				if !ok {
+					report(conf, file, d.LineNumber, "default registered for %s, which isn't documented in any struct", d.FieldName)
+					unmatched = append(unmatched, d)
+					continue
+				}
+				matched[key] = true
+				if p.field.Sensitive {
+					continue
+				}
+				if p.field.DefaultValue == "" {
+					p.field.DefaultValue = d.DefaultValue
+					p.field.DefaultUnknown = false
+				} else if p.field.DefaultValue != d.DefaultValue {
+					report(conf, file, d.LineNumber, "default registered for %s (%s) differs from the documented one (%s)", d.FieldName, d.DefaultValue, p.field.DefaultValue)
+				}
+			}
+
+			if len(unmatched) > 0 {
+				filesConfigs[file][resources.DefaultsSectionName] = unmatched
+			} else {
+				delete(filesConfigs[file], resources.DefaultsSectionName)
+				if len(filesConfigs[file]) == 0 {
+					delete(filesConfigs, file)
+				}
+			}
+		}
+
+		if !registered || !conf.ReportMissingDefaults {
+			continue
+		}
+		keys := make([]string, 0, len(paths))
+		for k := range paths {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !matched[k] {
+				p := paths[k]
+				report(conf, p.file, p.field.LineNumber, "no default registered for documented key %s", k)
+			}
+		}
+	}
+}
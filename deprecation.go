@@ -0,0 +1,71 @@
+package cato
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+type deprecatedField struct {
+	file    string
+	section string
+	field   *resources.FieldInfo
+}
+
+// writeDeprecationReport summarises the deprecated fields of all the files in
+// a markdown table, sorted by file and section.
+func writeDeprecationReport(filesConfigs map[string]map[string][]*resources.FieldInfo, rootPath, reportPath string) error {
+	deprecated := []deprecatedField{}
+	for file, configs := range filesConfigs {
+		rel, err := filepath.Rel(rootPath, file)
+		if err != nil {
+			return err
+		}
+		for _, s := range utils.SortedSections(configs) {
+			for _, f := range configs[s] {
+				if f.Deprecation != nil {
+					deprecated = append(deprecated, deprecatedField{filepath.ToSlash(rel), s, f})
+				}
+			}
+		}
+	}
+	sort.SliceStable(deprecated, func(i, j int) bool {
+		return deprecated[i].file < deprecated[j].file
+	})
+
+	if !filepath.IsAbs(reportPath) {
+		reportPath = path.Join(rootPath, reportPath)
+	}
+	if err := os.MkdirAll(path.Dir(reportPath), 0700); err != nil {
+		return err
+	}
+	fo, err := os.Create(reportPath)
+	if err != nil {
+		return err
+	}
+	defer fo.Close()
+
+	w := bufio.NewWriter(fo)
+	fmt.Fprintln(w, "# Deprecated configuration")
+	fmt.Fprintln(w)
+	if len(deprecated) == 0 {
+		fmt.Fprintln(w, "No field is deprecated.")
+		return w.Flush()
+	}
+	fmt.Fprintln(w, "| Field | Defined in | Added in | Replaced by | Removed in | Notes |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- | --- |")
+	for _, d := range deprecated {
+		fmt.Fprintf(w, "| %s.%s | %s:%d | %s | %s | %s | %s |\n",
+			d.section, d.field.FieldName, d.file, d.field.LineNumber, d.field.Since,
+			d.field.Deprecation.ReplacedBy, d.field.Deprecation.RemovedIn,
+			strings.ReplaceAll(d.field.Deprecation.Message, "|", "\\|"))
+	}
+	return w.Flush()
+}
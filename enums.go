@@ -0,0 +1,80 @@
+package cato
+
+import (
+	"go/ast"
+	"path"
+	"strings"
+
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+// enumType returns the name of the named type of a field, or of the elements
+// of a list.
+func enumType(dataType string) string {
+	expr := utils.ParseType(dataType)
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+			continue
+		case *ast.ArrayType:
+			if t.Len == nil {
+				expr = t.Elt
+				continue
+			}
+		case *ast.Ident:
+			return t.Name
+		}
+		return ""
+	}
+}
+
+// getEnums returns the constants declared for every named type of the package,
+// in order of declaration.
+func getEnums(idx *constIndex) map[string][]resources.EnumValue {
+	enums := map[string][]resources.EnumValue{}
+	for _, d := range idx.order {
+		if d.typeName == "" || !idx.types[d.typeName] {
+			continue
+		}
+		v, ok := idx.value(d.name)
+		if !ok {
+			continue
+		}
+		enums[d.typeName] = append(enums[d.typeName], resources.EnumValue{
+			Name:        d.name,
+			Value:       formatConst(v),
+			Description: d.doc,
+		})
+	}
+	return enums
+}
+
+// resolveEnums documents the allowed values of the fields typed with a named
+// type of their package for which constants are declared.
+func resolveEnums(filesConfigs map[string]map[string][]*resources.FieldInfo, consts map[string]*constIndex) {
+	enums := map[string]map[string][]resources.EnumValue{}
+	for dir, idx := range consts {
+		enums[dir] = getEnums(idx)
+	}
+
+	for file, configs := range filesConfigs {
+		for _, fields := range configs {
+			for _, f := range fields {
+				name := enumType(f.DataType)
+				values, ok := enums[path.Dir(file)][name]
+				if !ok {
+					continue
+				}
+				f.Enum = values
+				// only the defaults of the fields typed string are quoted
+				// during the extraction
+				if f.DataType == name && f.DefaultValue != "" && f.DefaultRef == "" && !strings.HasPrefix(f.DefaultValue, "\"") && !strings.HasPrefix(f.DefaultValue, "url:") &&
+					strings.HasPrefix(values[0].Value, "\"") {
+					f.DefaultValue = "\"" + f.DefaultValue + "\""
+				}
+			}
+		}
+	}
+}
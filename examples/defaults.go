@@ -0,0 +1,18 @@
+package main
+
+// settings mimics the registration of defaults of libraries such as viper.
+type settings map[string]interface{}
+
+func (s settings) SetDefault(key string, value interface{}) {
+	s[key] = value
+}
+
+func registerDefaults(s settings) {
+	s.SetDefault("cachedirectory", "/var/tmp/")
+	s.SetDefault("logformat", "text")
+	s.SetDefault("uploads.http_prefix", "uploads")
+	s.SetDefault("uploads.max_file_size", 1048576)
+	s.SetDefault("uploads.workers", 4)
+	s.SetDefault("uploads.jwt_secret", "changeme")
+	s.SetDefault("uploads.chunk_size", 65536)
+}
@@ -3,20 +3,55 @@ package main
 import "fmt"
 
 type FileSystem struct {
-	CacheDirectory     string   `docs:"/var/tmp/;Path of cache directory"`
-	EnableLogging      bool     `docs:"false;Whether to enable logging"`
+	CacheDirectory     string   `env:"CACHE_DIRECTORY" docs:"/var/tmp/;Path of cache directory"`
+	EnableLogging      bool     `env:"ENABLE_LOGGING" docs:"false;Whether to enable logging;group=Logging"`
 	AvailableChecksums []string `docs:"[adler, rabin];The list of checksums provided by the file system"`
-	// Configs for various metadata drivers
+	// Configs for various metadata drivers, keyed by the name of the driver.
+	// Every driver accepts:
+	//   - encoding, the encoding of the metadata
+	//
+	// For example, the JSON driver is configured as in
+	//
+	//	[DriverConfig.json]
+	//	encoding = "UTF8"
+	//
+	// See https://github.com/cs3org/cato/tree/master/examples and [fmt.Sprint]
+	// for the formatting of the values.
 	DriverConfig map[string]map[string]interface{} `docs:"{json:{encoding: UTF8}, xml:{encoding: ASCII}}"`
 	// Config for the HTTP uploads service
 	Uploads *UploadConfig `docs:"&UploadConfig{HTTPPrefix: uploads, DisableTus: false}"`
+	// The level of the logs
+	LogLevel string `validate:"oneof=debug info warn error" docs:"info;group=Logging"`
+	// The format of the logs
+	LogFormat    LogFormat `docs:"text;group=Logging"`
+	DumpRequests bool      `docs:"false;visibility=internal"` // Whether to dump the requests, for debugging purposes
 }
 
+// UploadConfig configures the HTTP uploads service.
+//
+//cato:group Services
 type UploadConfig struct {
 	// Whether to disable TUS protocol for uploads.
-	DisableTus bool `json:"disable_tus" docs:"false"`
+	DisableTus bool `json:"disable_tus" docs:"false;deprecated=TUS is always enabled;removed-in=2.0"`
 	// The prefix at which the uploads service should be exposed.
-	HTTPPrefix string `json:"http_prefix" docs:"uploads"`
+	HTTPPrefix string `json:"http_prefix" env:"HTTP_PREFIX" docs:"uploads;weight=-1"`
+	// The maximum size of the uploaded files.
+	MaxFileSize int64 `json:"max_file_size" default:"1048576" validate:"required,min=1" docs:"max=1073741824;unit=bytes"`
+	Workers     int   `json:"workers" docs:"default=DefaultUploadWorkers;The number of uploads processed concurrently.;since=1.1;visibility=advanced"`
+	// The prefix at which the uploads service should be exposed.
+	Prefix string `json:"prefix" docs:"uploads;replaced-by=http_prefix;since=0.9"`
+	// Whether to serve the uploads without TLS.
+	Insecure bool `json:"insecure" docs:"false;group=TLS"`
+	// The path of the TLS certificate.
+	CertFile string `json:"cert_file" docs:"required=true;when=insecure=false;group=TLS"`
+	// The path of the TLS key.
+	KeyFile string `json:"key_file" docs:"required=true;when=Insecure=false;group=TLS"`
+	// The users allowed to upload files.
+	AllowedUsers []string `json:"allowed_users" docs:"exclusive=acl"`
+	// The groups allowed to upload files.
+	AllowedGroups []string `json:"allowed_groups" docs:"exclusive=acl"`
+	// The secret used to sign the upload URLs.
+	JWTSecret string `json:"jwt_secret" docs:"changeme;secret"`
 }
 
 func (fs FileSystem) init() {
@@ -49,3 +84,16 @@ func main() {
 	}
 	fmt.Printf("FileSystem: %+v", fs)
 }
+
+// LogFormat is the format in which the logs are written.
+type LogFormat string
+
+const (
+	// LogFormatText writes the logs as human readable lines.
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON writes every log as a JSON object.
+	LogFormatJSON LogFormat = "json"
+)
+
+// DefaultUploadWorkers is the default number of upload workers.
+const DefaultUploadWorkers = 4
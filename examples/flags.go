@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+	"time"
+)
+
+var (
+	configFile = flag.String("config", "/etc/filesystem/filesystem.toml", "Path of the config file")
+	logLevel   string
+	gcInterval time.Duration
+	tags       []string
+)
+
+func init() {
+	flag.StringVar(&logLevel, "log-level", "info", "The level of the logs")
+	flag.DurationVar(&gcInterval, "gc-interval", 10*time.Minute, "The interval between garbage collections")
+	flag.Func("tag", "A tag of the node, can be repeated", func(s string) error {
+		tags = append(tags, s)
+		return nil
+	})
+}
@@ -0,0 +1,34 @@
+package main
+
+import "time"
+
+// Option configures a FileSystem.
+type Option func(fs *FileSystem)
+
+// WithCacheDirectory sets the path of the cache directory.
+func WithCacheDirectory(dir string) Option {
+	return func(fs *FileSystem) {
+		fs.CacheDirectory = dir
+	}
+}
+
+// WithChecksums sets the list of checksums provided by the file system.
+func WithChecksums(checksums ...string) Option {
+	return func(fs *FileSystem) {
+		fs.AvailableChecksums = checksums
+	}
+}
+
+// WithUploadTimeout sets the timeout of the uploads.
+func WithUploadTimeout(d time.Duration) Option {
+	return func(fs *FileSystem) {}
+}
+
+// NewFileSystem returns a FileSystem configured with the given options.
+func NewFileSystem(opts ...Option) *FileSystem {
+	fs := &FileSystem{}
+	for _, o := range opts {
+		o(fs)
+	}
+	return fs
+}
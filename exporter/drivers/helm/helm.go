@@ -0,0 +1,171 @@
+package helm
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cs3org/cato/exporter"
+	"github.com/cs3org/cato/exporter/drivers/registry"
+	"github.com/cs3org/cato/exporter/sample"
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	valuesFile    = "values.yaml"
+	configMapFile = "configmap.yaml"
+
+	configMapHeader = "apiVersion: v1\n" +
+		"kind: ConfigMap\n" +
+		"metadata:\n" +
+		"  name: %s\n" +
+		"data:\n" +
+		"  %s: |"
+)
+
+var invalidNameRegex = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// maxNameLength is the maximum length of the names of ConfigMaps, which are
+// DNS labels.
+const maxNameLength = 63
+
+func init() {
+	registry.Register("helm", New)
+}
+
+type mgr struct {
+	c    *config
+	pkgs utils.Packages
+}
+
+type config struct {
+	DocPaths map[string]string
+	// PathKeys nests the values of every package under the keys obtained by
+	// splitting its path relative to the matching DocPaths entry.
+	PathKeys bool
+	// ConfigMap enables the generation of a ConfigMap manifest embedding the
+	// sample config of every package.
+	ConfigMap bool
+	// ConfigMapFormat is the format of the embedded config, one of toml
+	// (default), yaml or json.
+	ConfigMapFormat string
+}
+
+func parseConfig(m map[string]interface{}) (*config, error) {
+	c := &config{}
+	if err := mapstructure.Decode(m, c); err != nil {
+		return nil, err
+	}
+	switch c.ConfigMapFormat {
+	case "":
+		c.ConfigMapFormat = "toml"
+	case "toml", "yaml", "json":
+	default:
+		return nil, fmt.Errorf("unsupported config map format: %s", c.ConfigMapFormat)
+	}
+	return c, nil
+}
+
+func New(m map[string]interface{}) (exporter.ConfigExporter, error) {
+	conf, err := parseConfig(m)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing conf: %w", err)
+	}
+
+	mgr := &mgr{
+		c:    conf,
+		pkgs: utils.Packages{},
+	}
+	return mgr, nil
+}
+
+// configMapName returns the name of the ConfigMap of a package, i.e. the base
+// of its path turned into a DNS label, or config if nothing is left of it.
+func configMapName(configName string) string {
+	name := invalidNameRegex.ReplaceAllString(strings.ToLower(filepath.Base(configName)), "-")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "config"
+	}
+	return name
+}
+
+func (m mgr) writeConfigMap(dir, configName string, nodes []*sample.Node) error {
+	var keys []string
+	if m.c.PathKeys && configName != "." {
+		keys = strings.Split(filepath.ToSlash(configName), "/")
+	}
+
+	b := bytes.Buffer{}
+	var err error
+	switch m.c.ConfigMapFormat {
+	case "toml":
+		err = sample.EncodeTOML(&b, strings.Join(keys, "."), nodes)
+	case "yaml":
+		err = sample.EncodeYAML(&b, sample.Nest(keys, nodes))
+	case "json":
+		err = sample.EncodeJSON(&b, sample.Nest(keys, nodes))
+	}
+	if err != nil {
+		return err
+	}
+
+	name := configMapName(configName)
+
+	fo, err := os.Create(filepath.Join(dir, configMapFile))
+	if err != nil {
+		return err
+	}
+	defer fo.Close()
+
+	w := bufio.NewWriter(fo)
+	fmt.Fprintf(w, configMapHeader+"\n", name, name+"."+m.c.ConfigMapFormat)
+	scanner := bufio.NewScanner(&b)
+	for scanner.Scan() {
+		fmt.Fprintln(w, strings.TrimRight("    "+scanner.Text(), " "))
+	}
+	return w.Flush()
+}
+
+func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath, rootPath string) error {
+	valuesDir, configName, err := utils.GetDocsDir(m.c.DocPaths, filePath, rootPath)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(valuesDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	// the whole package is rewritten every time one of its files is exported
+	nodes := sample.Build(m.pkgs.Add(valuesDir, configs))
+
+	values := nodes
+	if m.c.PathKeys && configName != "." {
+		values = sample.Nest(strings.Split(filepath.ToSlash(configName), "/"), nodes)
+	}
+
+	fo, err := os.Create(path.Join(valuesDir, valuesFile))
+	if err != nil {
+		return err
+	}
+	defer fo.Close()
+	if err := sample.EncodeHelmValues(fo, values); err != nil {
+		return err
+	}
+
+	if m.c.ConfigMap {
+		return m.writeConfigMap(valuesDir, configName, nodes)
+	}
+	return nil
+}
@@ -12,14 +12,22 @@ import (
 
 	"github.com/cs3org/cato/exporter"
 	"github.com/cs3org/cato/exporter/drivers/registry"
+	"github.com/cs3org/cato/exporter/utils"
 	"github.com/cs3org/cato/resources"
 	"github.com/mitchellh/mapstructure"
 )
 
-const configDefaultTemplate = "  <li><b>{{ .Config.FieldName}}</b> - {{ .Config.DataType}}</li>\n" +
+const configDefaultTemplate = "  <li><b>{{ .Config.FieldName}}</b> - {{ if ne .TypeName .Config.DataType}}<span title=\"{{ .Config.DataType}}\">{{ .TypeName}}</span>{{ else}}{{ .Config.DataType}}{{ end}}{{ with .Config.Unit}} ({{ .}}){{ end}}{{ if .Config.Sensitive}} <mark>sensitive</mark>{{ end}}{{ with .Config.Visibility}} <mark>{{ .}}</mark>{{ end}}</li>\n" +
 	"  <ul>\n" +
-	"    <li>{{ .Config.Description}} {{ .ReferenceURL}}</li>\n" +
-	"    <li>Default: {{ .EscapedDefaultValue}}</li>\n" +
+	"    <li>{{ .Description}} {{ .ReferenceURL}}{{ with .Details}}\n{{ .}}\n    {{ end}}</li>\n" +
+	"{{ with .Config.Deprecation}}    <li><b>Deprecated</b>{{ with .String}}: {{ .}}{{ end}}</li>\n{{ end}}" +
+	"{{ with .Config.Since}}    <li>Since: {{ .}}</li>\n{{ end}}" +
+	"{{ if .EscapedDefaultValue}}    <li>Default: {{ .EscapedDefaultValue}}{{ with .HumanDefault}} ({{ .}}){{ end}}</li>\n{{ else if .Config.DefaultUnknown}}    <li>Default: unknown</li>\n{{ end}}" +
+	"{{ with .Config.Constraints.String}}    <li>Constraints: {{ .}}</li>\n{{ end}}" +
+	"{{ with .Config.Enum}}    <li>Allowed values:\n      <ul>\n" +
+	"{{ range .}}        <li><code>{{ .Value}}</code>{{ with .Description}} - {{ .}}{{ end}}</li>\n{{ end}}" +
+	"      </ul>\n    </li>\n{{ end}}" +
+	"{{ if .Env}}    <li>Environment: {{ .Env}}</li>\n{{ end}}" +
 	"  </ul>"
 
 func init() {
@@ -27,18 +35,30 @@ func init() {
 }
 
 type mgr struct {
-	c *config
+	c     *config
+	types *utils.TypeNamer
 }
 
 type config struct {
 	DocPaths      map[string]string
 	ReferenceBase string
+	// TypeFormat is the config format, toml, yaml or json, for which the
+	// types of the fields are named, e.g. list of strings rather than
+	// []string. The go types are documented if it's empty.
+	TypeFormat string
+	// TypeNames overrides the names of go types, as written in the code.
+	TypeNames map[string]string
 }
 
 type templateParameters struct {
 	Config              *resources.FieldInfo
+	TypeName            string
+	Description         string
+	Details             string
 	EscapedDefaultValue string
+	HumanDefault        string
 	ReferenceURL        string
+	Env                 string
 }
 
 func parseConfig(m map[string]interface{}) (*config, error) {
@@ -55,8 +75,14 @@ func New(m map[string]interface{}) (exporter.ConfigExporter, error) {
 		return nil, fmt.Errorf("error parsing conf: %w", err)
 	}
 
+	types, err := utils.NewTypeNamer(conf.TypeFormat, conf.TypeNames)
+	if err != nil {
+		return nil, err
+	}
+
 	mgr := &mgr{
-		c: conf,
+		c:     conf,
+		types: types,
 	}
 	return mgr, nil
 }
@@ -94,11 +120,26 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 
 	lines := []string{}
 
-	for s, fields := range configs {
-		lines = append(lines, fmt.Sprintf("\n<h2>struct: %s</h2>", s))
+	group := ""
+	for _, s := range utils.SortedSections(configs) {
+		fields := configs[s]
+		section := resources.Section(s, fields)
+		if section.Group != "" && section.Group != group {
+			lines = append(lines, "\n<h1>"+section.Group+"</h1>")
+		}
+		group = section.Group
+		lines = append(lines, fmt.Sprintf("\n<h2>%s</h2>", section.Title()))
 		lines = append(lines, "<ul>")
 
-		for _, f := range fields {
+		envSection := utils.EnvSection(fields)
+
+		fieldGroup := ""
+		for _, f := range utils.SortedFields(fields) {
+			if h := utils.GroupHeading(fieldGroup, f.Group); h != "" {
+				lines = append(lines, "</ul>\n<h3>"+h+"</h3>\n<ul>")
+			}
+			fieldGroup = f.Group
+
 			var escapedDefaultValue string
 			var isPointer bool
 			if strings.HasPrefix(f.DefaultValue, "url:") {
@@ -119,10 +160,17 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 				refURL = fmt.Sprintf(`<a href="%s/%s#L%d">[Ref]</a>`, m.c.ReferenceBase, reference, f.LineNumber)
 			}
 
+			description, details := utils.FormatDoc(f, "html")
+
 			params := templateParameters{
 				Config:              f,
+				TypeName:            m.types.TypeName(f, configs),
+				Description:         description,
+				Details:             details,
 				EscapedDefaultValue: escapedDefaultValue,
+				HumanDefault:        utils.HumanDefault(f),
 				ReferenceURL:        refURL,
+				Env:                 utils.Env(f, envSection, "<code>%s</code>"),
 			}
 
 			b := bytes.Buffer{}
@@ -0,0 +1,84 @@
+package json
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cs3org/cato/exporter"
+	"github.com/cs3org/cato/exporter/drivers/registry"
+	"github.com/cs3org/cato/exporter/sample"
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+	"github.com/mitchellh/mapstructure"
+)
+
+const defaultFileName = "example.json"
+
+func init() {
+	registry.Register("json", New)
+}
+
+type mgr struct {
+	c    *config
+	pkgs utils.Packages
+}
+
+type config struct {
+	DocPaths map[string]string
+	// FileName is the name of the sample config created for every package.
+	FileName string
+	// PathKeys nests the keys of every package under the keys obtained by
+	// splitting its path relative to the matching DocPaths entry.
+	PathKeys bool
+}
+
+func parseConfig(m map[string]interface{}) (*config, error) {
+	c := &config{}
+	if err := mapstructure.Decode(m, c); err != nil {
+		return nil, err
+	}
+	if c.FileName == "" {
+		c.FileName = defaultFileName
+	}
+	return c, nil
+}
+
+func New(m map[string]interface{}) (exporter.ConfigExporter, error) {
+	conf, err := parseConfig(m)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing conf: %w", err)
+	}
+
+	mgr := &mgr{
+		c:    conf,
+		pkgs: utils.Packages{},
+	}
+	return mgr, nil
+}
+
+func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath, rootPath string) error {
+	sampleDir, configName, err := utils.GetDocsDir(m.c.DocPaths, filePath, rootPath)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(sampleDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	// the whole package is rewritten every time one of its files is exported
+	nodes := sample.Build(m.pkgs.Add(sampleDir, configs))
+	if m.c.PathKeys && configName != "." {
+		nodes = sample.Nest(strings.Split(filepath.ToSlash(configName), "/"), nodes)
+	}
+
+	fo, err := os.Create(path.Join(sampleDir, m.c.FileName))
+	if err != nil {
+		return err
+	}
+	defer fo.Close()
+	return sample.EncodeJSON(fo, nodes)
+}
@@ -0,0 +1,416 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cs3org/cato/exporter"
+	"github.com/cs3org/cato/exporter/drivers/registry"
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	fileSuffix  = ".schema.json"
+)
+
+func init() {
+	registry.Register("jsonschema", New)
+}
+
+type mgr struct {
+	c *config
+}
+
+type config struct {
+	DocPaths map[string]string
+	// IDBase is the URL under which the generated schemas are published.
+	// If set, it's used to populate the $id of the schemas.
+	IDBase string
+}
+
+// schema is the subset of the JSON Schema vocabulary used by the driver.
+type schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           *properties        `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              json.Number        `json:"minimum,omitempty"`
+	Maximum              json.Number        `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+	AllOf                []*schema          `json:"allOf,omitempty"`
+	OneOf                []*schema          `json:"oneOf,omitempty"`
+	AnyOf                []*schema          `json:"anyOf,omitempty"`
+	Not                  *schema            `json:"not,omitempty"`
+	If                   *schema            `json:"if,omitempty"`
+	Then                 *schema            `json:"then,omitempty"`
+	Defs                 map[string]*schema `json:"$defs,omitempty"`
+	WriteOnly            bool               `json:"writeOnly,omitempty"`
+	XEnv                 string             `json:"x-env,omitempty"`
+	XSensitive           bool               `json:"x-sensitive,omitempty"`
+	XUnit                string             `json:"x-unit,omitempty"`
+}
+
+type property struct {
+	name   string
+	schema *schema
+}
+
+// properties preserves the order in which the fields are declared.
+type properties []property
+
+func (p properties) MarshalJSON() ([]byte, error) {
+	b := bytes.Buffer{}
+	b.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(prop.name)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(prop.schema)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// builder generates the schema of a root struct, inlining the structs used by
+// a single field and referencing through $defs those used by several.
+type builder struct {
+	configs map[string][]*resources.FieldInfo
+	refs    map[string]int
+	defs    map[string]*schema
+	visited map[string]bool
+}
+
+func parseConfig(m map[string]interface{}) (*config, error) {
+	c := &config{}
+	if err := mapstructure.Decode(m, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func New(m map[string]interface{}) (exporter.ConfigExporter, error) {
+	conf, err := parseConfig(m)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing conf: %w", err)
+	}
+
+	mgr := &mgr{
+		c: conf,
+	}
+	return mgr, nil
+}
+
+func (b *builder) structSchema(name string) *schema {
+	if b.refs[name] > 1 || b.visited[name] {
+		if _, ok := b.defs[name]; !ok {
+			b.defs[name] = nil
+			b.defs[name] = b.objectSchema(name)
+		}
+		// the names of instantiations hold brackets, escaped in the fragment
+		return &schema{Ref: "#/$defs/" + url.PathEscape(name)}
+	}
+	return b.objectSchema(name)
+}
+
+func (b *builder) objectSchema(name string) *schema {
+	b.visited[name] = true
+	defer delete(b.visited, name)
+
+	fields := utils.Fields(b.configs, name)
+	props := properties{}
+	required := []string{}
+	for _, f := range utils.SortedFields(fields) {
+		props = append(props, property{name: f.FieldName, schema: b.fieldSchema(f)})
+		c := f.Constraints
+		if c.Required && c.When == nil && c.Exclusive == "" {
+			required = append(required, f.FieldName)
+		}
+	}
+	s := &schema{
+		Type:       "object",
+		Properties: &props,
+		Required:   required,
+		AllOf:      conditions(fields),
+	}
+	// a single group is expressed directly, several need to hold together
+	groups := exclusiveGroups(fields)
+	if len(groups) == 1 {
+		s.OneOf = groups[0]
+	} else {
+		for _, g := range groups {
+			s.AllOf = append(s.AllOf, &schema{OneOf: g})
+		}
+	}
+	return s
+}
+
+// conditions requires the fields required under a condition once it holds.
+func conditions(fields []*resources.FieldInfo) []*schema {
+	types := map[string]string{}
+	for _, f := range fields {
+		types[f.FieldName] = f.DataType
+	}
+
+	var all []*schema
+	for _, f := range fields {
+		w := f.Constraints.When
+		if !f.Constraints.Required || w == nil {
+			continue
+		}
+		var v interface{} = w.Value
+		expr := utils.ParseType(types[w.Field])
+		if utils.Kind(expr) != "string" {
+			if lit, err := utils.ParseLiteral(w.Value); err == nil {
+				v = lit
+			}
+		}
+		if c, ok := utils.Coerce(v, expr); ok {
+			v = c
+		}
+		all = append(all, &schema{
+			If: &schema{
+				Properties: &properties{{name: w.Field, schema: &schema{Enum: []interface{}{v}}}},
+				Required:   []string{w.Field},
+			},
+			Then: &schema{Required: []string{f.FieldName}},
+		})
+	}
+	return all
+}
+
+// exclusiveGroups allows at most one field of every exclusive group to be set,
+// or exactly one if any of them is required.
+func exclusiveGroups(fields []*resources.FieldInfo) [][]*schema {
+	groups := []string{}
+	members := map[string][]string{}
+	required := map[string]bool{}
+	for _, f := range fields {
+		g := f.Constraints.Exclusive
+		if g == "" {
+			continue
+		}
+		if _, ok := members[g]; !ok {
+			groups = append(groups, g)
+		}
+		members[g] = append(members[g], f.FieldName)
+		required[g] = required[g] || f.Constraints.Required
+	}
+	oneOf := make([][]*schema, 0, len(groups))
+	for _, g := range groups {
+		alternatives := []*schema{}
+		for _, name := range members[g] {
+			alternatives = append(alternatives, &schema{Required: []string{name}})
+		}
+		if !required[g] {
+			alternatives = append(alternatives, &schema{Not: &schema{AnyOf: alternatives}})
+		}
+		oneOf = append(oneOf, alternatives)
+	}
+	return oneOf
+}
+
+func (b *builder) fieldSchema(f *resources.FieldInfo) *schema {
+	s := b.typeSchema(utils.ParseType(f.DataType))
+	s.Description = strings.Join(utils.DocLines(f), "\n")
+	s.XEnv = f.EnvName
+	s.Deprecated = f.Deprecation != nil
+	s.WriteOnly, s.XSensitive = f.Sensitive, f.Sensitive
+	s.XUnit = utils.Unit(f)
+	if utils.StructRef(f.DataType, b.configs) == "" {
+		if v, ok := utils.ParseDefault(f); ok {
+			s.Default = v
+		}
+	}
+	applyConstraints(s, f.Constraints, utils.ParseType(f.DataType))
+	applyEnum(s, f.Enum)
+	return s
+}
+
+// applyEnum lists the constants declared for the type of a field, or of its
+// elements, unless the allowed values are already restricted.
+func applyEnum(s *schema, enum []resources.EnumValue) {
+	if len(enum) == 0 {
+		return
+	}
+	if s.Type == "array" && s.Items != nil {
+		s = s.Items
+	}
+	if len(s.Enum) > 0 {
+		return
+	}
+	for _, e := range enum {
+		v, err := utils.ParseLiteral(e.Value)
+		if err != nil {
+			continue
+		}
+		if s.Type == "" {
+			switch v.(type) {
+			case string:
+				s.Type = "string"
+			case int64:
+				s.Type = "integer"
+			case float64:
+				s.Type = "number"
+			case bool:
+				s.Type = "boolean"
+			}
+		}
+		s.Enum = append(s.Enum, v)
+	}
+}
+
+// applyConstraints maps the constraints of a field to the keywords matching
+// its kind, i.e. bounds apply to the value of numbers but to the length of
+// strings and lists.
+func applyConstraints(s *schema, c resources.Constraints, expr ast.Expr) {
+	bound := func(v string, number *json.Number, length **int) {
+		if v == "" {
+			return
+		}
+		switch s.Type {
+		case "integer", "number":
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				*number = json.Number(v)
+			}
+		default:
+			if n, err := strconv.Atoi(v); err == nil {
+				*length = &n
+			}
+		}
+	}
+	switch s.Type {
+	case "array":
+		bound(c.Min, &s.Minimum, &s.MinItems)
+		bound(c.Max, &s.Maximum, &s.MaxItems)
+	case "string", "integer", "number":
+		bound(c.Min, &s.Minimum, &s.MinLength)
+		bound(c.Max, &s.Maximum, &s.MaxLength)
+	}
+
+	for _, o := range c.OneOf {
+		var v interface{} = o
+		if utils.Kind(expr) != "string" {
+			if lit, err := utils.ParseLiteral(o); err == nil {
+				v = lit
+			}
+		}
+		if v, ok := utils.Coerce(v, expr); ok {
+			s.Enum = append(s.Enum, v)
+		}
+	}
+	s.Pattern = c.Pattern
+}
+
+func (b *builder) typeSchema(expr ast.Expr) *schema {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return b.typeSchema(t.X)
+	case *ast.Ident:
+		if _, ok := b.configs[t.Name]; ok {
+			return b.structSchema(t.Name)
+		}
+	case *ast.IndexExpr, *ast.IndexListExpr, *ast.SelectorExpr:
+		// instantiations of generic structs, whose fields are substituted,
+		// and inline structs named after their parent, e.g. Config.Cache
+		if ref := utils.StructRef(types.ExprString(t), b.configs); ref != "" {
+			return b.structSchema(ref)
+		}
+	case *ast.ArrayType:
+		if utils.Kind(t) == "array" {
+			return &schema{Type: "array", Items: b.typeSchema(t.Elt)}
+		}
+	case *ast.MapType:
+		return &schema{Type: "object", AdditionalProperties: b.typeSchema(t.Value)}
+	}
+
+	switch k := utils.Kind(expr); k {
+	case "", "any":
+		return &schema{}
+	default:
+		return &schema{Type: k}
+	}
+}
+
+func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath, rootPath string) error {
+	schemaDir, configName, err := utils.GetDocsDir(m.c.DocPaths, filePath, rootPath)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(schemaDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	refs := utils.References(configs)
+	for _, root := range utils.Roots(configs) {
+		b := &builder{
+			configs: configs,
+			refs:    refs,
+			defs:    map[string]*schema{},
+			visited: map[string]bool{},
+		}
+
+		s := b.objectSchema(root)
+		s.Schema = schemaDraft
+		s.Title = root
+		if len(b.defs) > 0 {
+			s.Defs = b.defs
+		}
+		if m.c.IDBase != "" {
+			s.ID = strings.TrimSuffix(m.c.IDBase, "/") + "/" + path.Join(filepath.ToSlash(configName), root+fileSuffix)
+		}
+
+		out, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fo, err := os.Create(path.Join(schemaDir, root+fileSuffix))
+		if err != nil {
+			return err
+		}
+		_, err = fo.Write(append(out, '\n'))
+		if cerr := fo.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
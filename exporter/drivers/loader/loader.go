@@ -1,7 +1,13 @@
 package loader
 
 import (
+	_ "github.com/cs3org/cato/exporter/drivers/env"
+	_ "github.com/cs3org/cato/exporter/drivers/helm"
 	_ "github.com/cs3org/cato/exporter/drivers/html"
+	_ "github.com/cs3org/cato/exporter/drivers/json"
+	_ "github.com/cs3org/cato/exporter/drivers/jsonschema"
 	_ "github.com/cs3org/cato/exporter/drivers/markdown"
 	_ "github.com/cs3org/cato/exporter/drivers/reva"
+	_ "github.com/cs3org/cato/exporter/drivers/toml"
+	_ "github.com/cs3org/cato/exporter/drivers/yaml"
 )
@@ -12,31 +12,50 @@ import (
 
 	"github.com/cs3org/cato/exporter"
 	"github.com/cs3org/cato/exporter/drivers/registry"
+	"github.com/cs3org/cato/exporter/utils"
 	"github.com/cs3org/cato/resources"
 	"github.com/mitchellh/mapstructure"
 )
 
-const configDefaultTemplate = "- **{{ .Config.FieldName}}** - {{ .Config.DataType}}\n" +
-	"  - {{ .Config.Description}} {{ .ReferenceURL}}\n" +
-	"  - Default: {{ .EscapedDefaultValue}}"
+const configDefaultTemplate = "- **{{ .Config.FieldName}}** - {{ .TypeName}}{{ if ne .TypeName .Config.DataType}} (`{{ .Config.DataType}}`){{ end}}{{ with .Config.Unit}} ({{ .}}){{ end}}{{ if .Config.Sensitive}} `sensitive`{{ end}}{{ with .Config.Visibility}} `{{ .}}`{{ end}}\n" +
+	"  - {{ .Description}} {{ .ReferenceURL}}" +
+	"{{ with .Details}}\n\n{{ .}}{{ end}}" +
+	"{{ with .Config.Deprecation}}\n  - **Deprecated**{{ with .String}}: {{ .}}{{ end}}{{ end}}" +
+	"{{ with .Config.Since}}\n  - Since: {{ .}}{{ end}}" +
+	"{{ if .EscapedDefaultValue}}\n  - Default: {{ .EscapedDefaultValue}}{{ with .HumanDefault}} ({{ .}}){{ end}}{{ else if .Config.DefaultUnknown}}\n  - Default: unknown{{ end}}" +
+	"{{ with .Config.Constraints.String}}\n  - Constraints: {{ .}}{{ end}}" +
+	"{{ with .Config.Enum}}\n  - Allowed values:{{ range .}}\n    - `{{ .Value}}`{{ with .Description}} - {{ .}}{{ end}}{{ end}}{{ end}}" +
+	"{{ if .Env}}\n  - Environment: {{ .Env}}{{ end}}"
 
 func init() {
 	registry.Register("markdown", New)
 }
 
 type mgr struct {
-	c *config
+	c     *config
+	types *utils.TypeNamer
 }
 
 type config struct {
 	DocPaths      map[string]string
 	ReferenceBase string
+	// TypeFormat is the config format, toml, yaml or json, for which the
+	// types of the fields are named, e.g. list of strings rather than
+	// []string. The go types are documented if it's empty.
+	TypeFormat string
+	// TypeNames overrides the names of go types, as written in the code.
+	TypeNames map[string]string
 }
 
 type templateParameters struct {
 	Config              *resources.FieldInfo
+	TypeName            string
+	Description         string
+	Details             string
 	EscapedDefaultValue string
+	HumanDefault        string
 	ReferenceURL        string
+	Env                 string
 }
 
 func parseConfig(m map[string]interface{}) (*config, error) {
@@ -53,8 +72,14 @@ func New(m map[string]interface{}) (exporter.ConfigExporter, error) {
 		return nil, fmt.Errorf("error parsing conf: %w", err)
 	}
 
+	types, err := utils.NewTypeNamer(conf.TypeFormat, conf.TypeNames)
+	if err != nil {
+		return nil, err
+	}
+
 	mgr := &mgr{
-		c: conf,
+		c:     conf,
+		types: types,
 	}
 	return mgr, nil
 }
@@ -92,10 +117,25 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 
 	lines := []string{}
 
-	for s, fields := range configs {
-		lines = append(lines, fmt.Sprintf("\n## struct: %s", s))
+	group := ""
+	for _, s := range utils.SortedSections(configs) {
+		fields := configs[s]
+		section := resources.Section(s, fields)
+		if section.Group != "" && section.Group != group {
+			lines = append(lines, "\n# "+section.Group)
+		}
+		group = section.Group
+		lines = append(lines, fmt.Sprintf("\n## %s", section.Title()))
+
+		envSection := utils.EnvSection(fields)
+
+		fieldGroup := ""
+		for _, f := range utils.SortedFields(fields) {
+			if h := utils.GroupHeading(fieldGroup, f.Group); h != "" {
+				lines = append(lines, "\n### "+h)
+			}
+			fieldGroup = f.Group
 
-		for _, f := range fields {
 			var escapedDefaultValue string
 			var isPointer bool
 			if strings.HasPrefix(f.DefaultValue, "url:") {
@@ -116,10 +156,17 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 				refURL = fmt.Sprintf("[[Ref]](%s/%s#L%d)", m.c.ReferenceBase, reference, f.LineNumber)
 			}
 
+			description, details := utils.FormatDoc(f, "markdown")
+
 			params := templateParameters{
 				Config:              f,
+				TypeName:            m.types.TypeName(f, configs),
+				Description:         description,
+				Details:             indent(details, "    "),
 				EscapedDefaultValue: escapedDefaultValue,
+				HumanDefault:        utils.HumanDefault(f),
 				ReferenceURL:        refURL,
+				Env:                 utils.Env(f, envSection, "`%s`"),
 			}
 
 			b := bytes.Buffer{}
@@ -150,3 +197,14 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 	}
 	return w.Flush()
 }
+
+// indent nests the non-empty lines of s in a list item.
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
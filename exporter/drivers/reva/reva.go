@@ -12,6 +12,7 @@ import (
 
 	"github.com/cs3org/cato/exporter"
 	"github.com/cs3org/cato/exporter/drivers/registry"
+	"github.com/cs3org/cato/exporter/utils"
 	"github.com/cs3org/cato/resources"
 	"github.com/mitchellh/mapstructure"
 )
@@ -19,26 +20,63 @@ import (
 const (
 	mdFile = "_index.md"
 
-	configDefaultTemplate = "{{`{{%`}} dir name=\"{{ .Config.FieldName}}\" type=\"{{ .Config.DataType}}\" default={{ .Config.DefaultValue}} {{`%}}`}}\n" +
-		"{{ .Config.Description}} {{ .ReferenceURL}}\n" +
+	configDefaultTemplate = "{{`{{%`}} dir name=\"{{ .Config.FieldName}}\" type=\"{{ .TypeName}}\" {{ if .EscapedDefaultValue}}default={{ .Config.DefaultValue}}{{ else if .Config.Sensitive}}default=\"<redacted>\"{{ else if .Config.DefaultUnknown}}default=\"unknown\"{{ else}}default=\"\"{{ end}} {{`%}}`}}\n" +
+		"{{ .Description}} {{ .ReferenceURL}}\n" +
+		"{{ with .Details}}\n{{ .}}\n\n{{ end}}" +
+		"{{ with .Config.Deprecation}}**Deprecated**{{ with .String}}: {{ .}}{{ end}}\n{{ end}}" +
+		"{{ with .Config.Since}}Since: {{ .}}\n{{ end}}" +
+		"{{ with .Config.Unit}}Unit: {{ .}}\n{{ end}}" +
+		"{{ with .HumanDefault}}Default: {{ .}}\n{{ end}}" +
+		"{{ if .Config.DefaultUnknown}}Default: unknown\n{{ end}}" +
+		"{{ if .Config.Sensitive}}**Sensitive**: the default value is redacted\n{{ end}}" +
+		"{{ with .Config.Visibility}}Visibility: {{ .}}\n{{ end}}" +
+		"{{ with .Config.Constraints.String}}Constraints: {{ .}}\n{{ end}}" +
+		"{{ with .Config.Enum}}Allowed values:\n{{ range .}}- `{{ .Value}}`{{ with .Description}} - {{ .}}{{ end}}\n{{ end}}{{ end}}" +
+		"{{ if .Env}}Environment: {{ .Env}}\n{{ end}}" +
 		"{{`{{< highlight toml >}}`}}\n" +
 		"[{{ .TomlPath}}]\n" +
-		"{{ .Config.FieldName}} = {{ .EscapedDefaultValue}}\n" +
+		"{{ if .EscapedDefaultValue}}{{ .TomlKey}} = {{ .EscapedDefaultValue}}{{ else if .Config.Sensitive}}# {{ .TomlKey}} = \"<redacted>\"{{ else}}# {{ .TomlKey}} = <{{ .TypeName}}>{{ end}}\n" +
 		"{{`{{< /highlight >}}`}}\n" +
 		"{{`{{% /dir %}}`}}\n"
 
-	configPointerTemplate = "{{`{{%`}} dir name=\"{{ .Config.FieldName}}\" type=\"{{ .Config.DataType}}\" default=\"{{ .Config.DefaultValue}}\" {{`%}}`}}\n" +
-		"{{ .Config.Description}} {{ .ReferenceURL}}\n" +
+	configPointerTemplate = "{{`{{%`}} dir name=\"{{ .Config.FieldName}}\" type=\"{{ .TypeName}}\" default=\"{{ if .EscapedDefaultValue}}{{ .Config.DefaultValue}}{{ else if .Config.Sensitive}}<redacted>{{ else if .Config.DefaultUnknown}}unknown{{ end}}\" {{`%}}`}}\n" +
+		"{{ .Description}} {{ .ReferenceURL}}\n" +
+		"{{ with .Details}}\n{{ .}}\n\n{{ end}}" +
+		"{{ with .Config.Deprecation}}**Deprecated**{{ with .String}}: {{ .}}{{ end}}\n{{ end}}" +
+		"{{ with .Config.Since}}Since: {{ .}}\n{{ end}}" +
+		"{{ with .Config.Unit}}Unit: {{ .}}\n{{ end}}" +
+		"{{ with .HumanDefault}}Default: {{ .}}\n{{ end}}" +
+		"{{ if .Config.DefaultUnknown}}Default: unknown\n{{ end}}" +
+		"{{ if .Config.Sensitive}}**Sensitive**: the default value is redacted\n{{ end}}" +
+		"{{ with .Config.Visibility}}Visibility: {{ .}}\n{{ end}}" +
+		"{{ with .Config.Constraints.String}}Constraints: {{ .}}\n{{ end}}" +
+		"{{ with .Config.Enum}}Allowed values:\n{{ range .}}- `{{ .Value}}`{{ with .Description}} - {{ .}}{{ end}}\n{{ end}}{{ end}}" +
+		"{{ if .Env}}Environment: {{ .Env}}\n{{ end}}" +
 		"{{`{{< highlight toml >}}`}}\n" +
 		"[{{ .TomlPath}}]\n" +
-		"{{ .EscapedDefaultValue}}\n" +
+		"{{ if .EscapedDefaultValue}}{{ .EscapedDefaultValue}}{{ else if .Config.Sensitive}}# {{ .TomlKey}} = \"<redacted>\"{{ else}}# {{ .TomlKey}} = <{{ .TypeName}}>{{ end}}\n" +
 		"{{`{{< /highlight >}}`}}\n" +
 		"{{`{{% /dir %}}`}}\n"
 
+	// the flags and the functional options aren't set in the config files, so
+	// they are documented without any TOML key
+	configFlagTemplate = "{{`{{%`}} dir name=\"-{{ .Config.FieldName}}\" type=\"{{ .TypeName}}\" {{`%}}`}}\n" +
+		"{{ .Description}} {{ .ReferenceURL}}\n" +
+		"{{ with .Config.DefaultValue}}Default: `{{ .}}`\n{{ end}}" +
+		"{{ with .HumanDefault}}Default: {{ .}}\n{{ end}}" +
+		"{{`{{% /dir %}}`}}\n"
+
+	configOptionTemplate = "{{`{{%`}} dir name=\"{{ .Config.FieldName}}\" type=\"{{ .TypeName}}\" {{`%}}`}}\n" +
+		"{{ .Description}} {{ .ReferenceURL}}\n" +
+		"{{ with .Details}}\n{{ .}}\n\n{{ end}}" +
+		"{{ with .Config.Deprecation}}**Deprecated**{{ with .String}}: {{ .}}{{ end}}\n{{ end}}" +
+		"{{ with .Config.Since}}Since: {{ .}}\n{{ end}}" +
+		"{{`{{% /dir %}}`}}\n"
+
 	headerTemplate = "---\n" +
 		"title: \"{{ .Name}}\"\n" +
 		"linkTitle: \"{{ .Name}}\"\n" +
-		"weight: 10\n" +
+		"weight: {{ .Weight}}\n" +
 		"description: >\n" +
 		"  Configuration for the {{ .Name}} service\n" +
 		"---"
@@ -49,19 +87,38 @@ func init() {
 }
 
 type mgr struct {
-	c *config
+	c     *config
+	types *utils.TypeNamer
 }
 
+// defaultWeight is the weight of the directories missing from Weights.
+const defaultWeight = 10
+
 type config struct {
 	DocPaths      map[string]string
 	ReferenceBase string
+	// TypeFormat is the config format, toml, yaml or json, for which the
+	// types of the fields are named, e.g. list of strings rather than
+	// []string. The go types are documented if it's empty.
+	TypeFormat string
+	// TypeNames overrides the names of go types, as written in the code.
+	TypeNames map[string]string
+	// Weights are the weights of the directories of the docs in the front
+	// matter of their index, by path relative to the root of the docs.
+	Weights map[string]int
 }
 
 type templateParameters struct {
 	Config              *resources.FieldInfo
+	TypeName            string
+	Description         string
+	Details             string
 	TomlPath            string
+	TomlKey             string
 	EscapedDefaultValue string
+	HumanDefault        string
 	ReferenceURL        string
+	Env                 string
 }
 
 func parseConfig(m map[string]interface{}) (*config, error) {
@@ -72,7 +129,7 @@ func parseConfig(m map[string]interface{}) (*config, error) {
 	return c, nil
 }
 
-func createMDFiles(root, mdDir string) error {
+func (m mgr) createMDFiles(root, mdDir string) error {
 	th, err := template.New("revaHeader").Parse(headerTemplate)
 	if err != nil {
 		return err
@@ -95,10 +152,21 @@ func createMDFiles(root, mdDir string) error {
 				}
 				defer f.Close()
 
+				rel, err := filepath.Rel(root, mdDir)
+				if err != nil {
+					return err
+				}
+				weight, ok := m.c.Weights[filepath.ToSlash(rel)]
+				if !ok {
+					weight = defaultWeight
+				}
+
 				svc := struct {
-					Name string
+					Name   string
+					Weight int
 				}{
-					Name: path.Base(mdDir),
+					Name:   path.Base(mdDir),
+					Weight: weight,
 				}
 				b := bytes.Buffer{}
 				err = th.Execute(&b, svc)
@@ -127,8 +195,14 @@ func New(m map[string]interface{}) (exporter.ConfigExporter, error) {
 		return nil, fmt.Errorf("error parsing conf: %w", err)
 	}
 
+	types, err := utils.NewTypeNamer(conf.TypeFormat, conf.TypeNames)
+	if err != nil {
+		return nil, err
+	}
+
 	mgr := &mgr{
-		c: conf,
+		c:     conf,
+		types: types,
 	}
 	return mgr, nil
 }
@@ -143,6 +217,14 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 	if err != nil {
 		return err
 	}
+	tf, err := template.New("revaFlag").Parse(configFlagTemplate)
+	if err != nil {
+		return err
+	}
+	to, err := template.New("revaOption").Parse(configOptionTemplate)
+	if err != nil {
+		return err
+	}
 
 	docFileSuffix, err := filepath.Rel(rootPath, path.Dir(filePath))
 	if err != nil {
@@ -165,7 +247,7 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 	mdDir := path.Join(docsRoot, configName)
 	docFile := path.Join(mdDir, mdFile)
 
-	err = createMDFiles(docsRoot, mdDir)
+	err = m.createMDFiles(docsRoot, mdDir)
 	if err != nil {
 		return err
 	}
@@ -179,9 +261,15 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 	configLineCount := 0
 	lines := []string{}
 
+	// the weight of the existing indexes is updated if configured
+	weight, hasWeight := m.c.Weights[filepath.ToSlash(configName)]
+
 	scanner := bufio.NewScanner(fi)
 	for scanner.Scan() {
 		currLine := scanner.Text()
+		if hasWeight && configLineCount == 1 && strings.HasPrefix(currLine, "weight:") {
+			currLine = fmt.Sprintf("weight: %d", weight)
+		}
 		lines = append(lines, currLine)
 		if strings.TrimSpace(currLine) == "---" {
 			configLineCount = configLineCount + 1
@@ -197,11 +285,27 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 
 	lines = append(lines, "")
 
-	for s, fields := range configs {
-		lines = append(lines, fmt.Sprintf("# _struct: %s_\n", s))
+	group := ""
+	for _, s := range utils.SortedSections(configs) {
+		fields := configs[s]
+		section := resources.Section(s, fields)
+		if section.Group != "" && section.Group != group {
+			lines = append(lines, "# "+section.Group+"\n")
+		}
+		group = section.Group
+		lines = append(lines, fmt.Sprintf("# _%s_\n", section.Title()))
+
+		envSection := utils.EnvSection(fields)
+
+		fieldGroup := ""
+		for _, f := range utils.SortedFields(fields) {
+			if h := utils.GroupHeading(fieldGroup, f.Group); h != "" {
+				lines = append(lines, "## "+h+"\n")
+			}
+			fieldGroup = f.Group
 
-		for _, f := range fields {
 			var escapedDefaultValue, tomlPath string
+			tomlKey := f.FieldName
 			var isPointer bool
 			if strings.HasPrefix(f.DefaultValue, "url:") {
 				defaultSplit := strings.SplitN(f.DefaultValue, ":", 3)
@@ -213,6 +317,14 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 				escapedDefaultValue = f.DefaultValue
 				tomlPath = strings.ReplaceAll(configName, "/", ".")
 			}
+			// registered defaults are named by their key path, whose last
+			// element is the key in the table of its parents
+			if section.Kind == resources.DefaultsSection {
+				if i := strings.LastIndex(tomlKey, "."); i >= 0 {
+					tomlPath += "." + tomlKey[:i]
+					tomlKey = tomlKey[i+1:]
+				}
+			}
 
 			var refURL string
 			if m.c.ReferenceBase != "" {
@@ -223,24 +335,35 @@ func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath,
 				refURL = fmt.Sprintf("[[Ref]](%s/%s#L%d)", m.c.ReferenceBase, reference, f.LineNumber)
 			}
 
+			description, details := utils.FormatDoc(f, "markdown")
+
 			params := templateParameters{
 				Config:              f,
+				TypeName:            m.types.TypeName(f, configs),
+				Description:         description,
+				Details:             details,
 				TomlPath:            tomlPath,
+				TomlKey:             tomlKey,
 				EscapedDefaultValue: escapedDefaultValue,
+				HumanDefault:        utils.HumanDefault(f),
 				ReferenceURL:        refURL,
+				Env:                 utils.Env(f, envSection, "`%s`"),
+			}
+
+			t := td
+			switch {
+			case section.Kind == resources.FlagsSection:
+				t = tf
+			case section.Kind == resources.OptionsSection:
+				t = to
+			case isPointer:
+				t = tp
 			}
 
 			b := bytes.Buffer{}
-			if isPointer {
-				err = tp.Execute(&b, params)
-				if err != nil {
-					return err
-				}
-			} else {
-				err = td.Execute(&b, params)
-				if err != nil {
-					return err
-				}
+			err = t.Execute(&b, params)
+			if err != nil {
+				return err
 			}
 			lines = append(lines, b.String())
 		}
@@ -0,0 +1,85 @@
+package toml
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/cs3org/cato/exporter"
+	"github.com/cs3org/cato/exporter/drivers/registry"
+	"github.com/cs3org/cato/exporter/sample"
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+	"github.com/mitchellh/mapstructure"
+)
+
+const defaultFileName = "example.toml"
+
+func init() {
+	registry.Register("toml", New)
+}
+
+type mgr struct {
+	c    *config
+	pkgs utils.Packages
+}
+
+type config struct {
+	DocPaths map[string]string
+	// FileName is the name of the sample config created for every package.
+	FileName string
+	// PathTables nests the keys of every package under a table named after
+	// its path relative to the matching DocPaths entry, as done in reva.
+	PathTables bool
+}
+
+func parseConfig(m map[string]interface{}) (*config, error) {
+	c := &config{}
+	if err := mapstructure.Decode(m, c); err != nil {
+		return nil, err
+	}
+	if c.FileName == "" {
+		c.FileName = defaultFileName
+	}
+	return c, nil
+}
+
+func New(m map[string]interface{}) (exporter.ConfigExporter, error) {
+	conf, err := parseConfig(m)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing conf: %w", err)
+	}
+
+	mgr := &mgr{
+		c:    conf,
+		pkgs: utils.Packages{},
+	}
+	return mgr, nil
+}
+
+func (m mgr) ExportConfigs(configs map[string][]*resources.FieldInfo, filePath, rootPath string) error {
+	sampleDir, configName, err := utils.GetDocsDir(m.c.DocPaths, filePath, rootPath)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(sampleDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	var table string
+	if m.c.PathTables && configName != "." {
+		table = strings.ReplaceAll(configName, "/", ".")
+	}
+
+	// the whole package is rewritten every time one of its files is exported
+	nodes := sample.Build(m.pkgs.Add(sampleDir, configs))
+
+	fo, err := os.Create(path.Join(sampleDir, m.c.FileName))
+	if err != nil {
+		return err
+	}
+	defer fo.Close()
+	return sample.EncodeTOML(fo, table, nodes)
+}
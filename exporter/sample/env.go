@@ -0,0 +1,70 @@
+package sample
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EncodeEnv writes the keys in nodes which can be set through environment
+// variables as a .env file, followed by the list of the keys which can only
+// be set in config files.
+func EncodeEnv(w io.Writer, nodes []*Node) error {
+	bw := bufio.NewWriter(w)
+	fileOnly := []string{}
+
+	var walk func(prefix string, nodes []*Node)
+	walk = func(prefix string, nodes []*Node) {
+		for _, n := range nodes {
+			key := prefix + n.Key
+			if len(n.Children) > 0 {
+				walk(key+".", n.Children)
+				continue
+			}
+			if n.Field == nil || n.Field.EnvName == "" {
+				fileOnly = append(fileOnly, key)
+				continue
+			}
+
+			for _, c := range commentLines("#", fieldComments(n.Field)) {
+				fmt.Fprintln(bw, c)
+			}
+			if n.Value != nil {
+				fmt.Fprintf(bw, "%s=%s\n", n.Field.EnvName, envValue(n.Value))
+			} else {
+				fmt.Fprintf(bw, "# %s=%s\n", n.Field.EnvName, n.Raw)
+			}
+		}
+	}
+	walk("", nodes)
+
+	if len(fileOnly) > 0 {
+		fmt.Fprintln(bw, "\n# The following keys can only be set in the config file:")
+		for _, k := range fileOnly {
+			fmt.Fprintf(bw, "# - %s\n", k)
+		}
+	}
+	return bw.Flush()
+}
+
+// envValue formats v as expected by envconfig, i.e. with the elements of
+// lists separated by commas and the entries of maps as key:value pairs.
+func envValue(v interface{}) string {
+	switch val := v.(type) {
+	case []interface{}:
+		elems := []string{}
+		for _, e := range val {
+			elems = append(elems, strings.Trim(envValue(e), "\""))
+		}
+		return quote(strings.Join(elems, ","))
+	case map[string]interface{}:
+		elems := []string{}
+		for _, k := range sortedKeys(val) {
+			elems = append(elems, k+":"+strings.Trim(envValue(val[k]), "\""))
+		}
+		return quote(strings.Join(elems, ","))
+	default:
+		return tomlValue(val)
+	}
+}
@@ -0,0 +1,53 @@
+package sample
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// object is a JSON object preserving the order of the keys.
+type object []*Node
+
+func (o object) MarshalJSON() ([]byte, error) {
+	b := bytes.Buffer{}
+	b.WriteByte('{')
+	first := true
+	for _, n := range o {
+		if len(n.Children) == 0 && n.Value == nil {
+			continue
+		}
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+		b.WriteString(quote(n.Key))
+		b.WriteByte(':')
+
+		var v interface{} = n.Value
+		if len(n.Children) > 0 {
+			v = object(n.Children)
+			if n.List {
+				v = []interface{}{v}
+			}
+		}
+		enc, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(enc)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// EncodeJSON writes the keys in nodes as a JSON document. Since JSON doesn't
+// support comments, the keys whose default is unknown are left out.
+func EncodeJSON(w io.Writer, nodes []*Node) error {
+	out, err := json.MarshalIndent(object(nodes), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(out, '\n'))
+	return err
+}
@@ -0,0 +1,176 @@
+// Package sample builds sample configurations out of the documented structs
+// and encodes them in the formats supported by the config decoders.
+package sample
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+// Node is a key of a sample configuration.
+type Node struct {
+	Key string
+	// Field is the documented field the key corresponds to. It is nil for the
+	// keys derived from the defaults of nested configs.
+	Field *resources.FieldInfo
+	// Value is the default value of the key, nil if it couldn't be determined.
+	Value interface{}
+	// Raw is the default value as found in the docs.
+	Raw string
+	// Children are the keys of the struct nested under this key.
+	Children []*Node
+	// List is set if the nested struct describes the elements of a list.
+	List bool
+}
+
+// Build returns the top-level keys of the sample configuration described by
+// configs, nesting the structs referred to by the fields of the top-level ones.
+func Build(configs map[string][]*resources.FieldInfo) []*Node {
+	nodes := []*Node{}
+	seen := map[string]bool{}
+	for _, root := range utils.Roots(configs) {
+		for _, n := range buildStruct(configs, root, map[string]bool{}) {
+			if !seen[n.Key] {
+				seen[n.Key] = true
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	return nodes
+}
+
+func buildStruct(configs map[string][]*resources.FieldInfo, name string, visiting map[string]bool) []*Node {
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	nodes := []*Node{}
+	for _, f := range utils.SortedFields(utils.Fields(configs, name)) {
+		nodes = append(nodes, buildField(configs, f, visiting))
+	}
+	return nodes
+}
+
+func buildField(configs map[string][]*resources.FieldInfo, f *resources.FieldInfo, visiting map[string]bool) *Node {
+	n := &Node{
+		Key:   f.FieldName,
+		Field: f,
+		Raw:   f.DefaultValue,
+	}
+
+	if ref := utils.StructRef(f.DataType, configs); ref != "" {
+		if visiting[ref] {
+			return n
+		}
+		switch deref(utils.ParseType(f.DataType)).(type) {
+		case *ast.ArrayType:
+			n.List = true
+		case *ast.MapType:
+			// the keys of the map aren't known in advance
+			return n
+		}
+		n.Children = buildStruct(configs, ref, visiting)
+		return n
+	}
+
+	if strings.HasPrefix(f.DefaultValue, "url:") {
+		n.Children = nestedDefaults(f.DefaultValue)
+		return n
+	}
+
+	if v, ok := utils.ParseDefault(f); ok {
+		n.Value = v
+	}
+	return n
+}
+
+// nestedDefaults converts the defaults of the nested configs, encoded as
+// url:driver:key = value lines, into a table named after the driver.
+func nestedDefaults(raw string) []*Node {
+	parts := strings.SplitN(raw, ":", 3)
+	if len(parts) != 3 {
+		return nil
+	}
+
+	driver := &Node{Key: parts[1]}
+	for _, line := range strings.Split(parts[2], "\n") {
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		c := &Node{
+			Key: strings.TrimSpace(kv[0]),
+			Raw: strings.TrimSpace(kv[1]),
+		}
+		if v, err := utils.ParseLiteral(c.Raw); err == nil {
+			c.Value = v
+		}
+		driver.Children = append(driver.Children, c)
+	}
+	return []*Node{driver}
+}
+
+// Comments returns the lines describing a key, to be added as comments above
+// it in the formats supporting them.
+func Comments(n *Node) []string {
+	if n.Field == nil {
+		return nil
+	}
+	comments := fieldComments(n.Field)
+	if n.Field.EnvName != "" {
+		comments = append(comments, "Environment variable: "+n.Field.EnvName)
+	}
+	return comments
+}
+
+// fieldComments returns the lines describing a field, independently of the
+// format in which it's set.
+func fieldComments(f *resources.FieldInfo) []string {
+	comments := utils.DocLines(f)
+	if f.Sensitive {
+		comments = append(comments, "Sensitive: keep the value out of version control")
+	}
+	if f.Deprecation != nil {
+		comments = append(comments, strings.TrimSuffix("Deprecated: "+f.Deprecation.String(), ": "))
+	}
+	if u := utils.Unit(f); u != "" {
+		if h := utils.HumanDefault(f); h != "" {
+			u += " (default " + h + ")"
+		}
+		comments = append(comments, "Unit: "+u)
+	}
+	if c := f.Constraints.String(); c != "" {
+		comments = append(comments, "Constraints: "+c)
+	}
+	if len(f.Enum) > 0 {
+		comments = append(comments, "Allowed values:")
+		for _, e := range f.Enum {
+			if e.Description != "" {
+				comments = append(comments, "- "+e.Value+": "+e.Description)
+			} else {
+				comments = append(comments, "- "+e.Value)
+			}
+		}
+	}
+	return comments
+}
+
+func deref(expr ast.Expr) ast.Expr {
+	for {
+		s, ok := expr.(*ast.StarExpr)
+		if !ok {
+			return expr
+		}
+		expr = s.X
+	}
+}
+
+// Nest nests nodes under the keys in path.
+func Nest(path []string, nodes []*Node) []*Node {
+	for i := len(path) - 1; i >= 0; i-- {
+		nodes = []*Node{{Key: path[i], Children: nodes}}
+	}
+	return nodes
+}
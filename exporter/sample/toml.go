@@ -0,0 +1,147 @@
+package sample
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var bareKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// EncodeTOML writes the keys in nodes as a commented TOML document. If table
+// is not empty, the keys are nested under the table with that dotted name.
+func EncodeTOML(w io.Writer, table string, nodes []*Node) error {
+	e := &tomlEncoder{w: bufio.NewWriter(w)}
+	e.table(table, false, nil, nodes)
+	return e.w.Flush()
+}
+
+type tomlEncoder struct {
+	w       *bufio.Writer
+	written bool
+}
+
+func (e *tomlEncoder) println(line string) {
+	fmt.Fprintln(e.w, line)
+	e.written = true
+}
+
+func (e *tomlEncoder) table(table string, list bool, comments []string, nodes []*Node) {
+	tables := []*Node{}
+	lines := []string{}
+	for _, n := range nodes {
+		if len(n.Children) > 0 {
+			tables = append(tables, n)
+			continue
+		}
+		lines = append(lines, commentLines("#", Comments(n))...)
+		if n.Value != nil {
+			lines = append(lines, fmt.Sprintf("%s = %s", tomlKey(n.Key), tomlValue(n.Value)))
+		} else {
+			lines = append(lines, strings.TrimSpace(fmt.Sprintf("# %s = %s", tomlKey(n.Key), n.Raw)))
+		}
+	}
+
+	if table != "" && (len(lines) > 0 || len(tables) == 0 || list) {
+		if e.written {
+			e.println("")
+		}
+		for _, c := range commentLines("#", comments) {
+			e.println(c)
+		}
+		if list {
+			e.println("[[" + table + "]]")
+		} else {
+			e.println("[" + table + "]")
+		}
+	}
+	for _, l := range lines {
+		e.println(l)
+	}
+
+	for _, t := range tables {
+		name := tomlKey(t.Key)
+		if table != "" {
+			name = table + "." + name
+		}
+		e.table(name, t.List, Comments(t), t.Children)
+	}
+}
+
+func commentLines(prefix string, comments []string) []string {
+	lines := []string{}
+	for _, c := range comments {
+		for _, l := range strings.Split(c, "\n") {
+			lines = append(lines, strings.TrimRight(prefix+" "+l, " "))
+		}
+	}
+	return lines
+}
+
+func tomlKey(k string) string {
+	if bareKeyRegex.MatchString(k) {
+		return k
+	}
+	return quote(k)
+}
+
+func tomlValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return quote(val)
+	case float64:
+		s := strconv.FormatFloat(val, 'f', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+		return s
+	case []interface{}:
+		elems := []string{}
+		for _, e := range val {
+			if e != nil {
+				elems = append(elems, tomlValue(e))
+			}
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case map[string]interface{}:
+		elems := []string{}
+		for _, k := range sortedKeys(val) {
+			if val[k] != nil {
+				elems = append(elems, tomlKey(k)+" = "+tomlValue(val[k]))
+			}
+		}
+		if len(elems) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(elems, ", ") + " }"
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// quote returns s as a double quoted string, using the escape sequences
+// common to TOML, YAML and JSON.
+func quote(s string) string {
+	b := bytes.Buffer{}
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return strconv.Quote(s)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,118 @@
+package sample
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var plainKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// EncodeYAML writes the keys in nodes as a commented YAML document.
+func EncodeYAML(w io.Writer, nodes []*Node) error {
+	e := &yamlEncoder{w: bufio.NewWriter(w)}
+	e.mapping(0, "", nodes)
+	return e.w.Flush()
+}
+
+// EncodeHelmValues writes the keys in nodes as a Helm values.yaml document,
+// with the comments formatted as expected by helm-docs. The keys whose default
+// is unknown are commented out, with plain comments which helm-docs doesn't
+// attach to the following key.
+func EncodeHelmValues(w io.Writer, nodes []*Node) error {
+	e := &yamlEncoder{w: bufio.NewWriter(w), helm: true}
+	e.mapping(0, "", nodes)
+	return e.w.Flush()
+}
+
+type yamlEncoder struct {
+	w    *bufio.Writer
+	helm bool
+}
+
+func (e *yamlEncoder) comments(n *Node) []string {
+	lines := commentLines("#", Comments(n))
+	if !e.helm || len(n.Children) == 0 && n.Value == nil {
+		return lines
+	}
+	if len(lines) > 0 {
+		lines[0] = "# --" + strings.TrimPrefix(lines[0], "#")
+	}
+	return lines
+}
+
+// mapping writes the nodes as the keys of a mapping. The first key is
+// prefixed with first, which is used to start the items of sequences.
+func (e *yamlEncoder) mapping(indent int, first string, nodes []*Node) {
+	for i, n := range nodes {
+		prefix := ""
+		if i == 0 {
+			prefix = first
+		} else if first != "" {
+			prefix = "  "
+		}
+		lead := strings.Repeat("  ", indent) + prefix
+		for _, c := range e.comments(n) {
+			fmt.Fprintln(e.w, lead+c)
+			lead = strings.Repeat("  ", indent) + strings.Repeat(" ", len(prefix))
+		}
+
+		key := yamlKey(n.Key) + ":"
+		switch {
+		case len(n.Children) > 0 && n.List:
+			fmt.Fprintln(e.w, lead+key)
+			e.mapping(indent+1, "- ", n.Children)
+		case len(n.Children) > 0:
+			fmt.Fprintln(e.w, lead+key)
+			e.mapping(indent+1+len(prefix)/2, "", n.Children)
+		case n.Value != nil:
+			e.value(lead+key, indent+1+len(prefix)/2, n.Value)
+		default:
+			fmt.Fprintln(e.w, strings.TrimRight(lead+"# "+key+" "+n.Raw, " "))
+		}
+	}
+}
+
+// value writes v after head, on the same line for scalars and empty
+// collections and on the following lines otherwise.
+func (e *yamlEncoder) value(head string, indent int, v interface{}) {
+	switch val := v.(type) {
+	case []interface{}:
+		if len(val) == 0 {
+			fmt.Fprintln(e.w, head+" []")
+			return
+		}
+		fmt.Fprintln(e.w, head)
+		for _, item := range val {
+			e.value(strings.Repeat("  ", indent)+"-", indent+1, item)
+		}
+	case map[string]interface{}:
+		if len(val) == 0 {
+			fmt.Fprintln(e.w, head+" {}")
+			return
+		}
+		fmt.Fprintln(e.w, head)
+		for _, k := range sortedKeys(val) {
+			e.value(strings.Repeat("  ", indent)+yamlKey(k)+":", indent+1, val[k])
+		}
+	case string:
+		fmt.Fprintln(e.w, head+" "+quote(val))
+	case nil:
+		fmt.Fprintln(e.w, head+" null")
+	default:
+		fmt.Fprintln(e.w, head+" "+tomlValue(val))
+	}
+}
+
+func yamlKey(k string) string {
+	switch strings.ToLower(k) {
+	case "true", "false", "yes", "no", "on", "off", "null", "y", "n", "~":
+		return quote(k)
+	}
+	if plainKeyRegex.MatchString(k) {
+		return k
+	}
+	return quote(k)
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"go/doc/comment"
+	"strings"
+
+	"github.com/cs3org/cato/resources"
+)
+
+var docPrinter = &comment.Printer{
+	DocLinkBaseURL: "https://pkg.go.dev",
+}
+
+// FormatDoc formats the description of a field as markdown or html, keeping
+// the lists, code blocks and links of its doc comment. The summary is the
+// first paragraph, documented along with the field, and the details are the
+// blocks following it, if any. The descriptions set through the custom tag
+// are returned as is.
+func FormatDoc(f *resources.FieldInfo, format string) (summary, details string) {
+	if f.Doc == nil || len(f.Doc.Content) == 0 {
+		return f.Description, ""
+	}
+
+	blocks := f.Doc.Content
+	if _, ok := blocks[0].(*comment.Paragraph); ok {
+		summary = printDoc(&comment.Doc{Content: blocks[:1], Links: f.Doc.Links}, format)
+		summary = strings.Join(strings.Fields(strings.TrimPrefix(summary, "<p>")), " ")
+		blocks = blocks[1:]
+	}
+	if len(blocks) > 0 {
+		details = printDoc(&comment.Doc{Content: blocks, Links: f.Doc.Links}, format)
+	}
+	return summary, strings.TrimRight(details, "\n")
+}
+
+// DocLines returns the lines of the description of a field as plain text, as
+// written in the comments of sample configs.
+func DocLines(f *resources.FieldInfo) []string {
+	if f.Doc == nil || len(f.Doc.Content) == 0 {
+		if f.Description == "" {
+			return nil
+		}
+		return []string{f.Description}
+	}
+	text := strings.TrimRight(string(docPrinter.Text(f.Doc)), "\n")
+	return strings.Split(text, "\n")
+}
+
+func printDoc(d *comment.Doc, format string) string {
+	if format == "html" {
+		return string(docPrinter.HTML(d))
+	}
+	return string(docPrinter.Markdown(d))
+}
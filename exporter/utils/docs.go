@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GetDocsDir returns the directory in which the documentation for the go file
+// at filePath should be created, along with the name of the config relative to
+// the matching entry in docPaths.
+func GetDocsDir(docPaths map[string]string, filePath, rootPath string) (string, string, error) {
+	docFileSuffix, err := filepath.Rel(rootPath, path.Dir(filePath))
+	if err != nil {
+		return "", "", err
+	}
+
+	var match string
+	for k := range docPaths {
+		if strings.HasPrefix(docFileSuffix, k) && len(k) > len(match) {
+			match = k
+		}
+	}
+
+	configName, err := filepath.Rel(match, docFileSuffix)
+	if err != nil {
+		return "", "", err
+	}
+
+	docsRoot := path.Join(rootPath, docPaths[match])
+	return path.Join(docsRoot, configName), configName, nil
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/cs3org/cato/resources"
+)
+
+// EnvSection returns whether any of the fields of a section can be set through
+// an environment variable. The fields without variables are only worth
+// mentioning as file-only if others have one.
+func EnvSection(fields []*resources.FieldInfo) bool {
+	for _, f := range fields {
+		if f.EnvName != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Env documents how a field can be set through the environment: the name of
+// its variable, formatted with format, file-only if envSection is set, or
+// nothing.
+func Env(f *resources.FieldInfo, envSection bool, format string) string {
+	if f.EnvName != "" {
+		return fmt.Sprintf(format, f.EnvName)
+	}
+	if envSection {
+		return "file-only"
+	}
+	return ""
+}
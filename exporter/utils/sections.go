@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/cs3org/cato/resources"
+)
+
+// SortedSections returns the names of the sections in configs in the order in
+// which they should be documented, i.e. by weight, group and name.
+func SortedSections(configs map[string][]*resources.FieldInfo) []string {
+	names := make([]string, 0, len(configs))
+	for s := range configs {
+		names = append(names, s)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := resources.Section(names[i], configs[names[i]]), resources.Section(names[j], configs[names[j]])
+		if a.Weight != b.Weight {
+			return a.Weight < b.Weight
+		}
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// SortedFields returns the fields of a section in the order in which they
+// should be documented. Every group, including the one of the fields without a
+// group, is placed according to the lightest of its fields, and where its
+// first field is declared for equal weights. The fields of a group are ordered
+// by weight, then declaration.
+func SortedFields(fields []*resources.FieldInfo) []*resources.FieldInfo {
+	groups := [][]*resources.FieldInfo{}
+	index := map[string]int{}
+	weights := []int{}
+	for _, f := range fields {
+		i, ok := index[f.Group]
+		if !ok {
+			i = len(groups)
+			index[f.Group] = i
+			groups = append(groups, nil)
+			weights = append(weights, f.Weight)
+		}
+		groups[i] = append(groups[i], f)
+		if f.Weight < weights[i] {
+			weights[i] = f.Weight
+		}
+	}
+
+	order := make([]int, len(groups))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return weights[order[i]] < weights[order[j]]
+	})
+
+	sorted := make([]*resources.FieldInfo, 0, len(fields))
+	for _, i := range order {
+		g := groups[i]
+		sort.SliceStable(g, func(i, j int) bool {
+			return g[i].Weight < g[j].Weight
+		})
+		sorted = append(sorted, g...)
+	}
+	return sorted
+}
+
+// GroupHeading returns the heading to document before a field of group
+// following one of prev, if any. The fields without a group following a group
+// are documented as Other.
+func GroupHeading(prev, group string) string {
+	switch {
+	case group == prev:
+		return ""
+	case group == "":
+		return "Other"
+	}
+	return group
+}
+
+// References returns the number of fields in configs whose type refers to each
+// of the documented structs.
+func References(configs map[string][]*resources.FieldInfo) map[string]int {
+	refs := map[string]int{}
+	for _, fields := range configs {
+		for _, f := range fields {
+			if s := StructRef(f.DataType, configs); s != "" {
+				refs[s]++
+				// the generic structs are referred to by their instantiations
+				if generic, _ := instantiation(ParseType(s)); generic != "" {
+					refs[generic]++
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// Roots returns the sorted names of the structs in configs which aren't
+// referenced by any field of the other structs, i.e. the top-level configs.
+// Sections which don't describe structs, such as flags, are left out.
+func Roots(configs map[string][]*resources.FieldInfo) []string {
+	refs := References(configs)
+	roots := []string{}
+	for _, s := range SortedSections(configs) {
+		if resources.Section(s, configs[s]).Kind != resources.StructSection {
+			continue
+		}
+		if refs[s] == 0 {
+			roots = append(roots, s)
+		}
+	}
+	return roots
+}
+
+// Packages accumulates the configs of the go files of each package, for the
+// drivers which generate a single document per package.
+type Packages map[string]map[string][]*resources.FieldInfo
+
+// Add merges configs into the ones collected for the package in dir and
+// returns the configs collected so far.
+func (p Packages) Add(dir string, configs map[string][]*resources.FieldInfo) map[string][]*resources.FieldInfo {
+	pkg, ok := p[dir]
+	if !ok {
+		pkg = map[string][]*resources.FieldInfo{}
+		p[dir] = pkg
+	}
+	for s, fields := range configs {
+		pkg[s] = fields
+	}
+	return pkg
+}
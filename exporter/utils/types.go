@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"github.com/cs3org/cato/resources"
+)
+
+// typeVocabulary holds the names of the composite values of a config format.
+type typeVocabulary struct {
+	list   string
+	table  string
+	number string
+}
+
+var typeFormats = map[string]typeVocabulary{
+	"toml": {list: "list", table: "table", number: "float"},
+	"yaml": {list: "list", table: "map", number: "number"},
+	"json": {list: "array", table: "object", number: "number"},
+}
+
+// wellKnownTypes are the names of the types of the standard library which are
+// decoded from strings.
+var wellKnownTypes = map[string]string{
+	"time.Duration": "string (duration)",
+	"time.Time":     "string (timestamp)",
+	"url.URL":       "string (URL)",
+	"net.IP":        "string (IP address)",
+	"[]byte":        "string (base64)",
+}
+
+// TypeNamer names the types of the fields for the users of a config format,
+// e.g. list of strings rather than []string.
+type TypeNamer struct {
+	vocabulary typeVocabulary
+	overrides  map[string]string
+}
+
+// NewTypeNamer returns a TypeNamer for the given format, which is one of toml,
+// yaml or json, or nil if format is empty, in which case the go types are
+// documented. The overrides map go types, as written in the code, to the
+// names they should be documented with.
+func NewTypeNamer(format string, overrides map[string]string) (*TypeNamer, error) {
+	if format == "" {
+		return nil, nil
+	}
+	v, ok := typeFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported type format: %s", format)
+	}
+	return &TypeNamer{vocabulary: v, overrides: overrides}, nil
+}
+
+// TypeName returns the name of the type of a field, which is its go type if n
+// is nil.
+func (n *TypeNamer) TypeName(f *resources.FieldInfo, configs map[string][]*resources.FieldInfo) string {
+	if n == nil {
+		return f.DataType
+	}
+	if name, ok := n.overrides[f.DataType]; ok {
+		return name
+	}
+	expr := ParseType(f.DataType)
+	if expr == nil {
+		return f.DataType
+	}
+	if id, ok := deref(expr).(*ast.Ident); ok && len(f.Enum) > 0 && configs[id.Name] == nil {
+		// the values of the constants tell the underlying type of enums
+		if strings.HasPrefix(f.Enum[0].Value, "\"") {
+			return "string"
+		}
+		return "integer"
+	}
+	return n.name(expr, configs)
+}
+
+func (n *TypeNamer) name(expr ast.Expr, configs map[string][]*resources.FieldInfo) string {
+	s := types.ExprString(expr)
+	if name, ok := n.overrides[s]; ok {
+		return name
+	}
+	if name, ok := wellKnownTypes[s]; ok {
+		return name
+	}
+
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return n.name(t.X, configs)
+	case *ast.ArrayType:
+		return n.vocabulary.list + " of " + plural(n.name(t.Elt, configs))
+	case *ast.MapType:
+		return n.vocabulary.table + " of " + plural(n.name(t.Value, configs))
+	case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		if StructRef(s, configs) != "" {
+			return n.vocabulary.table
+		}
+	}
+
+	switch Kind(expr) {
+	case "":
+		return s
+	case "any":
+		return "value"
+	case "number":
+		return n.vocabulary.number
+	default:
+		return Kind(expr)
+	}
+}
+
+// plural returns the plural of a type name, whose first word is the noun.
+func plural(name string) string {
+	i := strings.IndexByte(name, ' ')
+	if i < 0 {
+		i = len(name)
+	}
+	if strings.HasSuffix(name[:i], "s") {
+		return name
+	}
+	return name[:i] + "s" + name[i:]
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cs3org/cato/resources"
+)
+
+// durationUnits are the units whose values are rendered as durations.
+var durationUnits = map[string]time.Duration{
+	"nanoseconds":  time.Nanosecond,
+	"microseconds": time.Microsecond,
+	"milliseconds": time.Millisecond,
+	"seconds":      time.Second,
+	"minutes":      time.Minute,
+	"hours":        time.Hour,
+}
+
+var byteUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// Unit returns the unit of the values of a field, which is nanoseconds for
+// the fields typed time.Duration unless set otherwise.
+func Unit(f *resources.FieldInfo) string {
+	if f.Unit == "" && f.DataType == "time.Duration" {
+		return "nanoseconds"
+	}
+	return f.Unit
+}
+
+// HumanDefault returns the default value of a field in a human friendly form,
+// e.g. 30s or 1 MiB, if it's a number expressed in a known unit. It's empty
+// otherwise.
+func HumanDefault(f *resources.FieldInfo) string {
+	unit := Unit(f)
+	if unit == "" {
+		return ""
+	}
+	n, err := strconv.ParseInt(strings.Trim(f.DefaultValue, "\""), 10, 64)
+	if err != nil {
+		return ""
+	}
+
+	if d, ok := durationUnits[unit]; ok {
+		return (time.Duration(n) * d).String()
+	}
+	if unit == "bytes" {
+		return formatBytes(n)
+	}
+	return ""
+}
+
+// formatBytes formats a number of bytes with the largest binary prefix it
+// reaches, e.g. 1.5 KiB.
+func formatBytes(n int64) string {
+	if n < 1024 && n > -1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	v := float64(n)
+	i := -1
+	for (v >= 1024 || v <= -1024) && i < len(byteUnits)-1 {
+		v /= 1024
+		i++
+	}
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return s + " " + byteUnits[i]
+}
@@ -0,0 +1,430 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/cs3org/cato/resources"
+)
+
+// ParseType parses the data type of a field into a go expression.
+// It returns nil if the type can't be parsed.
+func ParseType(dataType string) ast.Expr {
+	expr, err := parser.ParseExpr(dataType)
+	if err != nil {
+		return nil
+	}
+	return expr
+}
+
+// StructRef returns the name of the struct among configs which describes the
+// values held by a field of type dataType, looking through pointers, slices and
+// map values. The instantiations of generic structs are named with their type
+// arguments, e.g. Pool[string], and their fields are returned by Fields. It
+// returns an empty string if there's no such struct.
+func StructRef(dataType string, configs map[string][]*resources.FieldInfo) string {
+	expr := ParseType(dataType)
+	for expr != nil {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ArrayType:
+			expr = t.Elt
+		case *ast.MapType:
+			expr = t.Value
+		case *ast.Ident:
+			if _, ok := configs[t.Name]; ok {
+				return t.Name
+			}
+			return ""
+		case *ast.IndexExpr, *ast.IndexListExpr:
+			if generic, _ := instantiation(expr); generic != "" && configs[generic] != nil {
+				return types.ExprString(expr)
+			}
+			return ""
+		case *ast.SelectorExpr:
+			// inline structs are named after the struct and field declaring
+			// them, e.g. Config.Options
+			if name := types.ExprString(expr); configs[name] != nil {
+				return name
+			}
+			return ""
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// Fields returns the fields of the struct documented under name in configs.
+// For the instantiations of generic structs, such as Pool[string], these are
+// the fields of the generic struct with the type arguments substituted for its
+// type parameters.
+func Fields(configs map[string][]*resources.FieldInfo, name string) []*resources.FieldInfo {
+	if fields, ok := configs[name]; ok {
+		return fields
+	}
+	generic, args := instantiation(ParseType(name))
+	fields := configs[generic]
+	params := resources.Section(generic, fields).TypeParams
+	if len(fields) == 0 || len(params) != len(args) {
+		return fields
+	}
+
+	subst := map[string]string{}
+	for i, p := range params {
+		subst[p] = types.ExprString(args[i])
+	}
+	instance := make([]*resources.FieldInfo, 0, len(fields))
+	for _, f := range fields {
+		c := *f
+		c.DataType = substitute(f.DataType, subst)
+		instance = append(instance, &c)
+	}
+	return instance
+}
+
+// instantiation returns the name of the generic struct instantiated by expr
+// and its type arguments, if it's an instantiation.
+func instantiation(expr ast.Expr) (string, []ast.Expr) {
+	var x ast.Expr
+	var args []ast.Expr
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		x, args = t.X, []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		x, args = t.X, t.Indices
+	}
+	if id, ok := x.(*ast.Ident); ok {
+		return id.Name, args
+	}
+	return "", nil
+}
+
+// substitute replaces the type parameters in a data type by their arguments.
+func substitute(dataType string, subst map[string]string) string {
+	expr := ParseType(dataType)
+	if expr == nil {
+		return dataType
+	}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch t := n.(type) {
+		case *ast.SelectorExpr:
+			// the names of other packages' types aren't parameters
+			return false
+		case *ast.Ident:
+			if a, ok := subst[t.Name]; ok {
+				t.Name = a
+			}
+		}
+		return true
+	})
+	return types.ExprString(expr)
+}
+
+// Kind returns the kind of values described by a type expression, which is
+// one of "string", "boolean", "integer", "number", "array", "object", "any" or
+// an empty string if it can't be determined.
+func Kind(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return "string"
+		case "bool":
+			return "boolean"
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
+			return "integer"
+		case "float32", "float64":
+			return "number"
+		case "any":
+			return "any"
+		}
+	case *ast.StarExpr:
+		return Kind(t.X)
+	case *ast.ArrayType:
+		if id, ok := t.Elt.(*ast.Ident); ok && id.Name == "byte" {
+			return "string"
+		}
+		return "array"
+	case *ast.MapType:
+		return "object"
+	case *ast.InterfaceType:
+		return "any"
+	}
+	return ""
+}
+
+// ParseDefault converts the default value of a field into a value of the go
+// type matching its data type, i.e. a string, bool, int64, float64,
+// []interface{} or map[string]interface{}. The second return value is false if
+// the field has no default or it couldn't be interpreted.
+func ParseDefault(f *resources.FieldInfo) (interface{}, bool) {
+	if f.DefaultValue == "" || strings.HasPrefix(f.DefaultValue, "url:") {
+		return nil, false
+	}
+
+	expr := ParseType(f.DataType)
+	if Kind(expr) == "string" {
+		// cato quotes the default values of string fields without escaping them
+		return strings.TrimSuffix(strings.TrimPrefix(f.DefaultValue, "\""), "\""), true
+	}
+
+	v, err := ParseLiteral(f.DefaultValue)
+	if err != nil {
+		return nil, false
+	}
+	return Coerce(v, expr)
+}
+
+// Coerce converts a value returned by ParseLiteral to the kind described by the
+// type expression.
+func Coerce(v interface{}, expr ast.Expr) (interface{}, bool) {
+	switch Kind(expr) {
+	case "string":
+		if v == nil {
+			return nil, false
+		}
+		return fmt.Sprint(v), true
+	case "boolean":
+		switch val := v.(type) {
+		case bool:
+			return val, true
+		case string:
+			b, err := strconv.ParseBool(val)
+			return b, err == nil
+		}
+	case "integer":
+		switch val := v.(type) {
+		case int64:
+			return val, true
+		case float64:
+			if val == float64(int64(val)) {
+				return int64(val), true
+			}
+		}
+	case "number":
+		switch val := v.(type) {
+		case int64:
+			return float64(val), true
+		case float64:
+			return val, true
+		}
+	case "array":
+		elt := deref(expr).(*ast.ArrayType).Elt
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		res := make([]interface{}, 0, len(list))
+		for _, e := range list {
+			c, ok := Coerce(e, elt)
+			if !ok {
+				return nil, false
+			}
+			res = append(res, c)
+		}
+		return res, true
+	case "object":
+		value := deref(expr).(*ast.MapType).Value
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		res := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			c, ok := Coerce(e, value)
+			if !ok {
+				return nil, false
+			}
+			res[k] = c
+		}
+		return res, true
+	case "any":
+		return v, true
+	default:
+		// named types which aren't documented structs, we can't do better than
+		// trusting the literal
+		if _, ok := v.(map[string]interface{}); ok {
+			return nil, false
+		}
+		return v, v != nil
+	}
+	return nil, false
+}
+
+func deref(expr ast.Expr) ast.Expr {
+	for {
+		s, ok := expr.(*ast.StarExpr)
+		if !ok {
+			return expr
+		}
+		expr = s.X
+	}
+}
+
+// ParseLiteral parses the loosely formatted literals used as default values in
+// the docs tags, such as `[adler, rabin]`, `{json:{encoding: UTF8}}` or
+// `&UploadConfig{HTTPPrefix: uploads}`. Lists are returned as []interface{},
+// composite values as map[string]interface{} and unquoted scalars as bool,
+// int64, float64 or string, depending on their format.
+func ParseLiteral(s string) (interface{}, error) {
+	p := &literalParser{s: s}
+	v, err := p.value("")
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.s[p.pos:], p.pos)
+	}
+	return v, nil
+}
+
+type literalParser struct {
+	s   string
+	pos int
+}
+
+func (p *literalParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *literalParser) value(stop string) (interface{}, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return nil, fmt.Errorf("unexpected end of literal")
+	}
+
+	switch c := p.s[p.pos]; c {
+	case '"', '\'', '`':
+		return p.quoted()
+	case '[':
+		return p.list()
+	case '{', '&':
+		return p.object()
+	}
+
+	// composite literals with a type name, e.g. UploadConfig{...}
+	if i := strings.IndexAny(p.s[p.pos:], "{,:[]} \t"); i > 0 && p.s[p.pos+i] == '{' {
+		return p.object()
+	}
+	return p.scalar(stop), nil
+}
+
+func (p *literalParser) quoted() (interface{}, error) {
+	q := p.s[p.pos]
+	for i := p.pos + 1; i < len(p.s); i++ {
+		switch p.s[i] {
+		case '\\':
+			if q != '`' {
+				i++
+			}
+		case q:
+			raw := p.s[p.pos : i+1]
+			p.pos = i + 1
+			if q == '\'' {
+				return raw[1 : len(raw)-1], nil
+			}
+			return strconv.Unquote(raw)
+		}
+	}
+	return nil, fmt.Errorf("unterminated string at offset %d", p.pos)
+}
+
+func (p *literalParser) list() (interface{}, error) {
+	p.pos++ // [
+	res := []interface{}{}
+	for {
+		p.skipSpace()
+		if p.pos < len(p.s) && p.s[p.pos] == ']' {
+			p.pos++
+			return res, nil
+		}
+		v, err := p.value(",]")
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+		if err := p.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *literalParser) object() (interface{}, error) {
+	i := strings.IndexByte(p.s[p.pos:], '{')
+	if i < 0 {
+		return nil, fmt.Errorf("expected '{' at offset %d", p.pos)
+	}
+	p.pos += i + 1
+
+	res := map[string]interface{}{}
+	for {
+		p.skipSpace()
+		if p.pos < len(p.s) && p.s[p.pos] == '}' {
+			p.pos++
+			return res, nil
+		}
+
+		k, err := p.value(":")
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.s) || p.s[p.pos] != ':' {
+			return nil, fmt.Errorf("expected ':' at offset %d", p.pos)
+		}
+		p.pos++
+
+		v, err := p.value(",}")
+		if err != nil {
+			return nil, err
+		}
+		res[fmt.Sprint(k)] = v
+		if err := p.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *literalParser) separator(end byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return fmt.Errorf("expected '%c' at end of literal", end)
+	}
+	switch p.s[p.pos] {
+	case ',':
+		p.pos++
+		return nil
+	case end:
+		return nil
+	}
+	return fmt.Errorf("unexpected '%c' at offset %d", p.s[p.pos], p.pos)
+}
+
+func (p *literalParser) scalar(stop string) interface{} {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(stop, p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	s := strings.TrimSpace(p.s[start:p.pos])
+
+	if s == "true" || s == "false" {
+		return s == "true"
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
@@ -0,0 +1,154 @@
+package cato
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strconv"
+
+	"github.com/cs3org/cato/resources"
+)
+
+type flagFunc struct {
+	dataType string
+	// positions of the name of the flag and of its usage in the arguments,
+	// the default value follows the name
+	nameArg  int
+	usageArg int
+	args     int
+	defaults bool
+}
+
+// flagFuncs are the functions of the flag package, and the methods of
+// flag.FlagSet, which define flags.
+var flagFuncs = map[string]flagFunc{
+	"Bool":        {"bool", 0, 2, 3, true},
+	"BoolVar":     {"bool", 1, 3, 4, true},
+	"BoolFunc":    {"bool", 0, 1, 3, false},
+	"Duration":    {"time.Duration", 0, 2, 3, true},
+	"DurationVar": {"time.Duration", 1, 3, 4, true},
+	"Float64":     {"float64", 0, 2, 3, true},
+	"Float64Var":  {"float64", 1, 3, 4, true},
+	"Func":        {"string", 0, 1, 3, false},
+	"Int":         {"int", 0, 2, 3, true},
+	"IntVar":      {"int", 1, 3, 4, true},
+	"Int64":       {"int64", 0, 2, 3, true},
+	"Int64Var":    {"int64", 1, 3, 4, true},
+	"String":      {"string", 0, 2, 3, true},
+	"StringVar":   {"string", 1, 3, 4, true},
+	"TextVar":     {"encoding.TextUnmarshaler", 1, 3, 4, true},
+	"Uint":        {"uint", 0, 2, 3, true},
+	"UintVar":     {"uint", 1, 3, 4, true},
+	"Uint64":      {"uint64", 0, 2, 3, true},
+	"Uint64Var":   {"uint64", 1, 3, 4, true},
+	"Var":         {"flag.Value", 1, 2, 3, false},
+}
+
+func printExpr(expr ast.Expr, fset *token.FileSet) (string, error) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// stringValue returns the value of expr if it's a string literal, and its
+// source otherwise.
+func stringValue(expr ast.Expr, fset *token.FileSet) (string, bool, error) {
+	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		s, err := strconv.Unquote(lit.Value)
+		return s, true, err
+	}
+	s, err := printExpr(expr, fset)
+	return s, false, err
+}
+
+// flagPackageName returns the name under which the flag package is imported in
+// the file, or an empty string if it isn't imported.
+func flagPackageName(fileTree *ast.File) string {
+	for _, imp := range fileTree.Imports {
+		if imp.Path.Value != `"flag"` {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return "flag"
+	}
+	return ""
+}
+
+func getFlagsToDocument(fileTree *ast.File, fset *token.FileSet, lineNos []int) ([]*resources.FieldInfo, error) {
+	flagPkg := flagPackageName(fileTree)
+	if flagPkg == "" || flagPkg == "_" {
+		return nil, nil
+	}
+
+	section := &resources.SectionInfo{Name: resources.FlagsSectionName, Kind: resources.FlagsSection}
+	flags := []*resources.FieldInfo{}
+	var err error
+
+	ast.Inspect(fileTree, func(node ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		fn, ok := flagFuncs[sel.Sel.Name]
+		if !ok || len(call.Args) != fn.args {
+			return true
+		}
+		// the methods of a FlagSet are called on any other receiver, so we only
+		// consider the calls having a literal flag name
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != flagPkg {
+			if lit, ok := call.Args[fn.nameArg].(*ast.BasicLit); !ok || lit.Kind != token.STRING {
+				return true
+			}
+		}
+
+		var name, usage, defaultVal string
+		var isLit bool
+		if name, _, err = stringValue(call.Args[fn.nameArg], fset); err != nil {
+			return false
+		}
+		if usage, _, err = stringValue(call.Args[fn.usageArg], fset); err != nil {
+			return false
+		}
+		if fn.defaults {
+			if defaultVal, isLit, err = stringValue(call.Args[fn.nameArg+1], fset); err != nil {
+				return false
+			}
+			if isLit {
+				defaultVal = fmt.Sprintf("\"%s\"", defaultVal)
+			}
+		}
+
+		var lineNumber int
+		if lineNumber, err = getLineNumber(lineNos, int(call.Pos())); err != nil {
+			return false
+		}
+
+		flags = append(flags, &resources.FieldInfo{
+			FieldName:    name,
+			DefaultValue: defaultVal,
+			Description:  usage,
+			DataType:     fn.dataType,
+			LineNumber:   lineNumber,
+			Section:      section,
+		})
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decoding flag: %w", err)
+	}
+
+	return flags, nil
+}
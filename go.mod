@@ -1,5 +1,9 @@
 module github.com/cs3org/cato
 
-go 1.14
+go 1.19
 
-require github.com/mitchellh/mapstructure v1.3.1
+require (
+	github.com/BurntSushi/toml v1.2.1
+	github.com/mitchellh/mapstructure v1.3.1
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,99 @@
+package cato
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/cs3org/cato/resources"
+)
+
+var namingPolicies = map[string]func(words []string) string{
+	"":      nil,
+	"as-is": nil,
+	"snake_case": func(words []string) string {
+		return strings.ToLower(strings.Join(words, "_"))
+	},
+	"kebab-case": func(words []string) string {
+		return strings.ToLower(strings.Join(words, "-"))
+	},
+	"lowerCamel": func(words []string) string {
+		var b strings.Builder
+		for i, w := range words {
+			if i == 0 {
+				b.WriteString(strings.ToLower(w))
+			} else {
+				b.WriteString(strings.ToUpper(w[:1]) + strings.ToLower(w[1:]))
+			}
+		}
+		return b.String()
+	},
+}
+
+// splitWords splits a Go identifier into words, keeping acronyms together,
+// e.g. HTTPPrefix into HTTP and Prefix.
+func splitWords(name string) []string {
+	words := []string{}
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, r := runes[i-1], runes[i]
+		switch {
+		case r == '_':
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)),
+			unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func checkNamingPolicy(policy string) error {
+	if _, ok := namingPolicies[policy]; !ok {
+		return fmt.Errorf("unknown naming policy: %s", policy)
+	}
+	return nil
+}
+
+// getFieldName returns the name of a field from the first tag of the
+// precedence list naming it, or by applying the naming policy to its Go name.
+// Tags naming the field differently are reported.
+func getFieldName(tag reflect.StructTag, goName string, conf *resources.CatoConfig, filePath string, lineNumber int) string {
+	tags := conf.TagPrecedence
+	if len(tags) == 0 {
+		tags = namedTags
+	}
+
+	var name, nameTag string
+	for _, t := range tags {
+		n := strings.Split(tag.Get(t), ",")[0]
+		// the field is ignored by this decoder
+		if n == "" || n == "-" {
+			continue
+		}
+		if name == "" {
+			name, nameTag = n, t
+		} else if n != name {
+			report(conf, filePath, lineNumber, "field %s is named %s by the %s tag but %s by the %s tag", goName, name, nameTag, n, t)
+		}
+	}
+	if name != "" {
+		return name
+	}
+
+	if f := namingPolicies[conf.NamingPolicy]; f != nil {
+		return f(splitWords(goName))
+	}
+	return goName
+}
@@ -0,0 +1,66 @@
+package cato
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/cs3org/cato/resources"
+)
+
+// optionType returns the name of the type returned by a function, if it
+// returns a single value.
+func optionType(fn *ast.FuncDecl) string {
+	if fn.Type.Results == nil || len(fn.Type.Results.List) != 1 || len(fn.Type.Results.List[0].Names) > 1 {
+		return ""
+	}
+	switch t := fn.Type.Results.List[0].Type.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		if id, ok := t.X.(*ast.Ident); ok {
+			return id.Name
+		}
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+func getOptionsToDocument(fileTree *ast.File, optionTypes []string, fset *token.FileSet, lineNos []int) ([]*resources.FieldInfo, error) {
+	isOptionType := map[string]bool{}
+	for _, t := range optionTypes {
+		isOptionType[t] = true
+	}
+
+	section := &resources.SectionInfo{Name: resources.OptionsSectionName, Kind: resources.OptionsSection}
+	options := []*resources.FieldInfo{}
+
+	for _, decl := range fileTree.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() || !isOptionType[optionType(fn)] {
+			continue
+		}
+
+		// the signature documents the parameters of the option
+		signature, err := printExpr(fn.Type, fset)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding option signature: %w", err)
+		}
+
+		lineNumber, err := getLineNumber(lineNos, int(fn.Pos()))
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, &resources.FieldInfo{
+			FieldName:   fn.Name.Name,
+			DataType:    signature,
+			Description: getDescription(fn.Doc),
+			LineNumber:  lineNumber,
+			Section:     section,
+		})
+	}
+
+	return options, nil
+}
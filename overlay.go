@@ -0,0 +1,227 @@
+package cato
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cs3org/cato/resources"
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v3"
+)
+
+// overlayEntry documents a field, or a struct, from a sidecar file rather
+// than from its code, e.g. for the structs of dependencies. The entries are
+// applied as directives of the field or struct, so the custom tag takes
+// precedence over them.
+type overlayEntry struct {
+	Name        string
+	Default     string
+	Description string
+	Attributes  map[string]interface{}
+
+	file    string
+	line    int
+	matched bool
+}
+
+// overlays holds the entries of the overlay files, by importpath.Type.Field or
+// importpath.Type.
+type overlays map[string]*overlayEntry
+
+// loadOverlays reads the overlay files at paths, relative to the root path
+// unless absolute. Their format is picked by extension: yaml, toml or json.
+func loadOverlays(paths []string, rootPath string) (overlays, error) {
+	o := overlays{}
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(rootPath, p)
+		}
+		raw, err := readOverlay(p)
+		if err != nil {
+			return nil, fmt.Errorf("error reading overlay %s: %w", p, err)
+		}
+		entries := map[string]*overlayEntry{}
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			WeaklyTypedInput: true,
+			Result:           &entries,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := decoder.Decode(raw); err != nil {
+			return nil, fmt.Errorf("error decoding overlay %s: %w", p, err)
+		}
+
+		lines, err := keyLines(p, entries)
+		if err != nil {
+			return nil, err
+		}
+		for key, e := range entries {
+			e.file, e.line = p, lines[key]
+			o[key] = e
+		}
+	}
+	return o, nil
+}
+
+func readOverlay(p string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	raw := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &raw)
+	case ".toml":
+		err = toml.Unmarshal(content, &raw)
+	case ".json":
+		err = json.Unmarshal(content, &raw)
+	default:
+		err = fmt.Errorf("unsupported overlay format")
+	}
+	return raw, err
+}
+
+// keyLines returns the line at which each key of entries first appears in the
+// file, for the diagnostics.
+func keyLines(p string, entries map[string]*overlayEntry) (map[string]int, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := map[string]int{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		for key := range entries {
+			if _, ok := lines[key]; !ok && strings.Contains(scanner.Text(), key) {
+				lines[key] = n
+			}
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// forPackage returns the entries of the package with the given import path,
+// by Type.Field or Type.
+func (o overlays) forPackage(importPath string) map[string]*overlayEntry {
+	entries := map[string]*overlayEntry{}
+	for key, e := range o {
+		name := strings.TrimPrefix(key, importPath+".")
+		if name != key && strings.Count(name, ".") <= 1 {
+			entries[name] = e
+		}
+	}
+	return entries
+}
+
+// reportUnmatched reports the entries which don't match any struct or field
+// of the documented code, e.g. because it changed since they were written.
+func (o overlays) reportUnmatched(conf *resources.CatoConfig) {
+	keys := make([]string, 0, len(o))
+	for key := range o {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if e := o[key]; !e.matched {
+			report(conf, e.file, e.line, "overlay entry %s doesn't match any struct or field", key)
+		}
+	}
+}
+
+// directives returns the entry as the directives of a field or struct.
+func (e *overlayEntry) directives() map[string]string {
+	directives := map[string]string{}
+	if e.Name != "" {
+		directives["name"] = e.Name
+	}
+	if e.Default != "" {
+		directives["default"] = e.Default
+	}
+	for k, v := range e.Attributes {
+		// boolean attributes such as sensitive are set by their presence
+		switch v := v.(type) {
+		case bool:
+			if v {
+				directives[k] = ""
+			}
+		default:
+			directives[k] = fmt.Sprint(v)
+		}
+	}
+	if len(directives) == 0 {
+		// an empty entry documents the field as is
+		directives["doc"] = ""
+	}
+	return directives
+}
+
+// applyOverlay merges the entry at key, if any, into the directives.
+func applyOverlay(entries map[string]*overlayEntry, key string, directives map[string]string) *overlayEntry {
+	e, ok := entries[key]
+	if !ok {
+		return nil
+	}
+	e.matched = true
+	for k, v := range e.directives() {
+		directives[k] = v
+	}
+	return e
+}
+
+// packageImportPath returns the import path of the package the file belongs
+// to, derived from its vendor directory or from the path of the module it's
+// part of. It falls back to the name of the package.
+func packageImportPath(filePath, pkgName string) string {
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return pkgName
+	}
+	slashed := filepath.ToSlash(dir)
+	if i := strings.LastIndex(slashed, "/vendor/"); i >= 0 {
+		return slashed[i+len("/vendor/"):]
+	}
+
+	for d := dir; ; d = filepath.Dir(d) {
+		if module := modulePath(filepath.Join(d, "go.mod")); module != "" {
+			rel, err := filepath.Rel(d, dir)
+			if err != nil {
+				return pkgName
+			}
+			return path.Join(module, filepath.ToSlash(rel))
+		}
+		if filepath.Dir(d) == d {
+			return pkgName
+		}
+	}
+}
+
+// modulePath returns the path of the module declared in a go.mod file, or an
+// empty string if it can't be read.
+func modulePath(goMod string) string {
+	content, err := os.ReadFile(goMod)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			if p, err := strconv.Unquote(fields[1]); err == nil {
+				return p
+			}
+			return fields[1]
+		}
+	}
+	return ""
+}
@@ -1,15 +1,260 @@
 package resources
 
+import (
+	"fmt"
+	"go/doc/comment"
+	"strings"
+)
+
+// Kinds of sections in which the fields are documented.
+const (
+	StructSection   = "struct"
+	FlagsSection    = "flags"
+	DefaultsSection = "defaults"
+	OptionsSection  = "options"
+)
+
+// Names of the sections which don't describe structs.
+const (
+	// FlagsSectionName holds the command-line flags defined in a file.
+	FlagsSectionName = "command-line flags"
+	// DefaultsSectionName holds the keys whose defaults are registered in a
+	// file through calls such as viper's SetDefault, but which don't match any
+	// field of the documented structs.
+	DefaultsSectionName = "registered defaults"
+	// OptionsSectionName holds the functional options defined in a file.
+	OptionsSectionName = "functional options"
+)
+
+// SectionInfo describes the section of the documentation a field belongs to.
+type SectionInfo struct {
+	Name string
+	Kind string
+	// Group and Weight are set through the //cato:group and //cato:weight
+	// directives of structs. The sections are ordered by weight, then group.
+	Group  string
+	Weight int
+	// TypeParams are the names of the type parameters of generic structs,
+	// which are documented once, with the parameters as the types of the
+	// fields using them.
+	TypeParams []string
+}
+
+// Title returns the heading under which the section is documented.
+func (s *SectionInfo) Title() string {
+	if s.Kind == StructSection {
+		if len(s.TypeParams) > 0 {
+			return "struct: " + s.Name + "[" + strings.Join(s.TypeParams, ", ") + "]"
+		}
+		return "struct: " + s.Name
+	}
+	return s.Name
+}
+
+// Section returns the section the fields documented under name belong to.
+func Section(name string, fields []*FieldInfo) *SectionInfo {
+	if len(fields) > 0 && fields[0].Section != nil {
+		return fields[0].Section
+	}
+	return &SectionInfo{Name: name, Kind: StructSection}
+}
+
+// Visibility levels of the fields, from the most to the least relevant to the
+// end users.
+const (
+	VisibilityBasic    = "basic"
+	VisibilityAdvanced = "advanced"
+	VisibilityInternal = "internal"
+)
+
+// Condition holds when another field of the same struct has the given value.
+type Condition struct {
+	Field string
+	Value string
+}
+
+// Constraints are the restrictions on the values accepted by a field.
+type Constraints struct {
+	Required bool
+	Min      string
+	Max      string
+	OneOf    []string
+	Pattern  string
+	// When is the condition under which the field is relevant, and required
+	// if Required is set.
+	When *Condition
+	// Exclusive is the group of mutually exclusive fields of the struct the
+	// field belongs to, and ExclusiveWith the other fields of the group. If
+	// any of them is required, one of the group has to be set.
+	Exclusive     string
+	ExclusiveWith []string
+}
+
+// String returns a human readable description of the constraints.
+func (c Constraints) String() string {
+	parts := []string{}
+	switch {
+	case c.Required && c.When != nil:
+		parts = append(parts, fmt.Sprintf("required when %s is %s", c.When.Field, c.When.Value))
+	case c.When != nil:
+		parts = append(parts, fmt.Sprintf("only relevant when %s is %s", c.When.Field, c.When.Value))
+	case c.Required:
+		parts = append(parts, "required")
+	}
+	if c.Min != "" {
+		parts = append(parts, "min: "+c.Min)
+	}
+	if c.Max != "" {
+		parts = append(parts, "max: "+c.Max)
+	}
+	if len(c.OneOf) > 0 {
+		parts = append(parts, "one of: "+strings.Join(c.OneOf, ", "))
+	}
+	if c.Pattern != "" {
+		parts = append(parts, "pattern: "+c.Pattern)
+	}
+	if len(c.ExclusiveWith) > 0 {
+		parts = append(parts, "mutually exclusive with "+strings.Join(c.ExclusiveWith, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Deprecation describes why and how a field is being phased out.
+type Deprecation struct {
+	Message string
+	// RemovedIn is the version in which the field is or will be removed.
+	RemovedIn string
+	// ReplacedBy is the key to use instead of the field.
+	ReplacedBy string
+}
+
+// String returns a human readable description of the deprecation.
+func (d *Deprecation) String() string {
+	parts := []string{}
+	if d.Message != "" {
+		parts = append(parts, d.Message)
+	}
+	if d.ReplacedBy != "" {
+		parts = append(parts, "replaced by "+d.ReplacedBy)
+	}
+	if d.RemovedIn != "" {
+		parts = append(parts, "removed in "+d.RemovedIn)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// EnumValue is one of the constants of the named type of a field.
+type EnumValue struct {
+	Name        string
+	Value       string
+	Description string
+}
+
 type FieldInfo struct {
 	FieldName    string
 	DataType     string
 	DefaultValue string
-	Description  string
-	LineNumber   int
+	// DefaultUnknown is set for the fields documented without the custom tag
+	// and whose default value couldn't be determined.
+	DefaultUnknown bool
+	// DefaultRef is the constant expression the default value is resolved
+	// from, if it's set through the default attribute of the custom tag.
+	DefaultRef  string
+	Description string
+	// Doc is the parsed doc comment the description is taken from, which the
+	// drivers render with its paragraphs, lists, code blocks and links. It's
+	// nil if the description is set through the custom tag.
+	Doc        *comment.Doc
+	LineNumber int
+	// EnvName is the environment variable which can be used to set the field.
+	// It's empty for the fields which can only be set in config files.
+	EnvName     string
+	Constraints Constraints
+	// Enum lists the allowed values of the fields typed with a named type for
+	// which constants are declared in the package.
+	Enum []EnumValue
+	// Since is the version in which the field was introduced.
+	Since string
+	// Deprecation is set if the field is deprecated.
+	Deprecation *Deprecation
+	// Unit is the unit of the values of the field, such as bytes or seconds.
+	Unit string
+	// Group is the group of related fields the field is documented with, and
+	// Weight orders the fields and groups of a section, the lightest first.
+	Group  string
+	Weight int
+	// Visibility is the level of the audience the field is documented for,
+	// empty for basic fields.
+	Visibility string
+	// Sensitive is set for the fields holding secrets, whose default values
+	// are redacted.
+	Sensitive bool
+	Section   *SectionInfo
+}
+
+// Diagnostic is an issue found while extracting the documentation.
+type Diagnostic struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Message)
 }
 
 type CatoConfig struct {
 	CustomTag    string
 	Driver       string
 	DriverConfig map[string]map[string]interface{}
+	// TagPrecedence are the tags the names of the fields are read from, in
+	// order of precedence. It defaults to json, mapstructure, xml, yaml and
+	// toml.
+	TagPrecedence []string
+	// NamingPolicy is used to name the fields without any of these tags:
+	// "as-is" (the default), "snake_case", "kebab-case" or "lowerCamel".
+	NamingPolicy string
+	// EnvPrefix is prepended to the names of all the environment variables.
+	EnvPrefix string
+	// EnvNesting is the scheme used to name the environment variables of the
+	// fields of nested structs. By default, they're prefixed with the name of
+	// the field referring to the struct, as done by envconfig; "none" leaves
+	// the names in their tags unchanged.
+	EnvNesting string
+	// UntaggedStructs are the names of the structs whose exported fields are
+	// all documented, even without the custom tag, or * for every struct.
+	// Structs can also be selected through the //cato:untagged directive.
+	UntaggedStructs []string
+	// Overlays are the paths of YAML, TOML or JSON files documenting the
+	// structs whose code can't be annotated, such as the ones of
+	// dependencies, relative to the root path. They map
+	// importpath.Type.Field keys to the name, default, description and
+	// attributes of the fields.
+	Overlays []string
+	// DocumentFlags enables the documentation of the command-line flags
+	// defined through the flag package.
+	DocumentFlags bool
+	// DefaultFuncs are the names of the functions registering default values
+	// for config keys, such as viper's SetDefault. Their calls are matched to
+	// the fields of the structs of the same package by key path.
+	DefaultFuncs []string
+	// ReportMissingDefaults reports the documented keys for which no default
+	// is registered, in the packages registering defaults through
+	// DefaultFuncs.
+	ReportMissingDefaults bool
+	// OptionTypes are the names of the types of the functional options, such
+	// as Option. The functions returning one of these types are documented
+	// along with their parameters.
+	OptionTypes []string
+	// Visibility lists the visibility levels of the fields rendered by each
+	// driver. The drivers which aren't listed render the basic and advanced
+	// fields.
+	Visibility map[string][]string
+	// DeprecationReport is the path of the markdown report summarising the
+	// deprecated fields, relative to the root path. No report is written if
+	// it's empty.
+	DeprecationReport string
+	// ReportDiagnostic is called with the issues found in the documented code.
+	// Diagnostics are discarded if it's nil.
+	ReportDiagnostic func(*Diagnostic)
 }
@@ -0,0 +1,275 @@
+package cato
+
+import (
+	"go/ast"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cs3org/cato/resources"
+)
+
+// docsAttributes are the attributes which can be set in the custom tag, as
+// key=value or, for booleans, just key following a positional value.
+var docsAttributes = map[string]bool{
+	"default":   true,
+	"required":  true,
+	"min":       true,
+	"max":       true,
+	"oneof":     true,
+	"pattern":   true,
+	"when":      true,
+	"exclusive": true,
+
+	"deprecated":  true,
+	"since":       true,
+	"removed-in":  true,
+	"replaced-by": true,
+
+	"secret":    true,
+	"sensitive": true,
+
+	"visibility": true,
+	"group":      true,
+	"weight":     true,
+	"unit":       true,
+}
+
+var secretNameRegex = regexp.MustCompile(`(?i)passw(or)?d|secret|token|api_?key|private_?key|credential`)
+
+// directivePrefix starts the comment directives read by cato.
+const directivePrefix = "//cato:"
+
+var oneOfRegex = regexp.MustCompile(`'[^']*'|\S+`)
+
+// docsTag holds the content of the custom tag: up to three positional values,
+// i.e. the name, default value and description, and the attributes.
+type docsTag struct {
+	values []string
+	attrs  map[string]string
+}
+
+func parseDocsTag(tag string) *docsTag {
+	t := &docsTag{attrs: map[string]string{}}
+	for _, v := range strings.Split(tag, ";") {
+		kv := strings.SplitN(v, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		// a bare word is only an attribute once a positional value precedes
+		// it, so that docs:"secret" still documents the default secret
+		if !docsAttributes[key] || len(kv) == 1 && len(t.values) == 0 {
+			t.values = append(t.values, v)
+			continue
+		}
+		if len(kv) == 2 {
+			t.attrs[key] = strings.TrimSpace(kv[1])
+		} else {
+			t.attrs[key] = ""
+		}
+	}
+	return t
+}
+
+func parseOneOf(s string) []string {
+	values := oneOfRegex.FindAllString(s, -1)
+	for i, v := range values {
+		values[i] = strings.Trim(v, "'")
+	}
+	return values
+}
+
+// getConstraints reads the constraints of a field from the tags of the
+// validation libraries, i.e. go-playground/validator's validate and binding
+// tags and envconfig's required tag, and from the attributes of the custom
+// tag, which take precedence.
+func getConstraints(tag reflect.StructTag, attrs map[string]string) resources.Constraints {
+	c := resources.Constraints{}
+
+	for _, validateTag := range []string{"validate", "binding"} {
+		for _, rule := range strings.Split(tag.Get(validateTag), ",") {
+			kv := strings.SplitN(rule, "=", 2)
+			var val string
+			if len(kv) == 2 {
+				val = kv[1]
+			}
+			switch kv[0] {
+			case "required":
+				c.Required = true
+			case "min", "gte":
+				c.Min = val
+			case "max", "lte":
+				c.Max = val
+			case "len":
+				c.Min, c.Max = val, val
+			case "oneof":
+				c.OneOf = parseOneOf(val)
+			case "pattern", "regexp":
+				c.Pattern = val
+			}
+		}
+	}
+	if tag.Get("required") == "true" {
+		c.Required = true
+	}
+
+	for k, v := range attrs {
+		switch k {
+		case "required":
+			c.Required = v != "false"
+		case "min":
+			c.Min = v
+		case "max":
+			c.Max = v
+		case "oneof":
+			c.OneOf = parseOneOf(v)
+		case "pattern":
+			c.Pattern = v
+		case "when":
+			kv := strings.SplitN(v, "=", 2)
+			c.When = &resources.Condition{Field: strings.TrimSpace(kv[0]), Value: "true"}
+			if len(kv) == 2 {
+				c.When.Value = strings.TrimSpace(kv[1])
+			}
+		case "exclusive":
+			c.Exclusive = v
+		}
+	}
+	return c
+}
+
+// getDeprecation returns the deprecation of a field described by the
+// attributes of the custom tag, which is implied by the version of removal or
+// the replacement.
+func getDeprecation(attrs map[string]string) *resources.Deprecation {
+	msg, deprecated := attrs["deprecated"]
+	removedIn, replacedBy := attrs["removed-in"], attrs["replaced-by"]
+	if !deprecated && removedIn == "" && replacedBy == "" {
+		return nil
+	}
+	return &resources.Deprecation{
+		Message:    msg,
+		RemovedIn:  removedIn,
+		ReplacedBy: replacedBy,
+	}
+}
+
+// isSensitive returns whether a field is marked as holding a secret, and
+// whether it's explicitly marked as not holding one.
+func isSensitive(attrs map[string]string) (sensitive, unmarked bool) {
+	for _, k := range []string{"secret", "sensitive"} {
+		if v, ok := attrs[k]; ok {
+			return v != "false", v == "false"
+		}
+	}
+	return false, false
+}
+
+// parseWeight parses the weight of a field or struct, reporting invalid ones.
+func parseWeight(w string, conf *resources.CatoConfig, filePath string, lineNumber int) int {
+	if w == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(w)
+	if err != nil {
+		report(conf, filePath, lineNumber, "invalid weight %s", w)
+	}
+	return n
+}
+
+// fieldDirectives are the directives of the doc comments of fields which
+// aren't attributes of the custom tag.
+var fieldDirectives = map[string]bool{
+	"doc":    true,
+	"ignore": true,
+	"name":   true,
+}
+
+// structDirectives are the directives of the doc comments of structs.
+var structDirectives = map[string]bool{
+	"doc":      true,
+	"ignore":   true,
+	"untagged": true,
+	"group":    true,
+	"weight":   true,
+}
+
+// checkDirectives reports the directives which aren't known.
+func checkDirectives(directives map[string]string, known func(string) bool, conf *resources.CatoConfig, filePath string, line int) {
+	for name := range directives {
+		if !known(name) {
+			report(conf, filePath, line, "unknown directive %s%s", directivePrefix, name)
+		}
+	}
+}
+
+// applyDirectives feeds the directives of a field into its custom tag, whose
+// attributes and values take precedence. Unlike the default attribute, which
+// refers to a constant, the default directive sets the value as is, e.g.
+// //cato:default 30s.
+func applyDirectives(t *docsTag, directives map[string]string) {
+	for k, v := range directives {
+		if _, ok := t.attrs[k]; ok || !docsAttributes[k] || k == "default" {
+			continue
+		}
+		t.attrs[k] = v
+	}
+	if d, ok := directives["default"]; ok && len(t.values) == 0 {
+		t.values = []string{d}
+	}
+}
+
+// parseDirectives returns the //cato: directives of a doc comment, such as
+// //cato:group Storage, by name.
+func parseDirectives(doc *ast.CommentGroup) map[string]string {
+	directives := map[string]string{}
+	if doc == nil {
+		return directives
+	}
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, directivePrefix) {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimPrefix(c.Text, directivePrefix), " ", 2)
+		directives[kv[0]] = ""
+		if len(kv) == 2 {
+			directives[kv[0]] = strings.TrimSpace(kv[1])
+		}
+	}
+	return directives
+}
+
+// resolveRelations replaces the fields the conditions refer to, which can be
+// given by their go name, with their documented name, and lists the other
+// fields of every exclusive group. The conditions on unknown fields are
+// reported.
+func resolveRelations(fields []*resources.FieldInfo, goNames []string, conf *resources.CatoConfig, filePath string) {
+	names := map[string]string{}
+	groups := map[string][]string{}
+	for i, f := range fields {
+		names[f.FieldName] = f.FieldName
+		names[goNames[i]] = f.FieldName
+		if g := f.Constraints.Exclusive; g != "" {
+			groups[g] = append(groups[g], f.FieldName)
+		}
+	}
+
+	for _, f := range fields {
+		if w := f.Constraints.When; w != nil {
+			if name, ok := names[w.Field]; ok {
+				w.Field = name
+			} else {
+				report(conf, filePath, f.LineNumber, "%s is conditioned on the unknown field %s", f.FieldName, w.Field)
+			}
+		}
+		if g := f.Constraints.Exclusive; g != "" {
+			for _, name := range groups[g] {
+				if name != f.FieldName {
+					f.Constraints.ExclusiveWith = append(f.Constraints.ExclusiveWith, name)
+				}
+			}
+			if len(groups[g]) == 1 {
+				report(conf, filePath, f.LineNumber, "%s is the only field of the exclusive group %s", f.FieldName, g)
+			}
+		}
+	}
+}
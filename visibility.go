@@ -0,0 +1,81 @@
+package cato
+
+import (
+	"github.com/cs3org/cato/exporter/utils"
+	"github.com/cs3org/cato/resources"
+)
+
+var defaultVisibility = []string{resources.VisibilityBasic, resources.VisibilityAdvanced}
+
+// getVisibility returns the visibility level set in the attributes of the
+// custom tag, hidden being an alias of internal. Unknown levels are reported
+// and considered basic.
+func getVisibility(attrs map[string]string, conf *resources.CatoConfig, filePath string, lineNumber int) string {
+	v, ok := attrs["visibility"]
+	if !ok {
+		return ""
+	}
+	switch v {
+	case resources.VisibilityBasic:
+		return ""
+	case resources.VisibilityAdvanced, resources.VisibilityInternal:
+		return v
+	case "hidden":
+		return resources.VisibilityInternal
+	}
+	report(conf, filePath, lineNumber, "unknown visibility level %s", v)
+	return ""
+}
+
+// filterVisibility returns the fields of configs whose visibility is among
+// levels. The structs only referred to by fields which aren't visible are
+// left out as well.
+func filterVisibility(configs map[string][]*resources.FieldInfo, levels []string) map[string][]*resources.FieldInfo {
+	visible := map[string]bool{}
+	for _, l := range levels {
+		if l == "hidden" {
+			l = resources.VisibilityInternal
+		}
+		visible[l] = true
+	}
+	isVisible := func(f *resources.FieldInfo) bool {
+		if f.Visibility == "" {
+			return visible[resources.VisibilityBasic]
+		}
+		return visible[f.Visibility]
+	}
+
+	filtered := map[string][]*resources.FieldInfo{}
+	dropped := []string{}
+	for s, fields := range configs {
+		kept := []*resources.FieldInfo{}
+		for _, f := range fields {
+			if isVisible(f) {
+				kept = append(kept, f)
+			} else if ref := utils.StructRef(f.DataType, configs); ref != "" {
+				dropped = append(dropped, ref)
+			}
+		}
+		if len(kept) > 0 {
+			filtered[s] = kept
+		}
+	}
+
+	// the structs which are no longer referred to would otherwise become
+	// top-level ones
+	for len(dropped) > 0 {
+		s := dropped[0]
+		dropped = dropped[1:]
+		fields, ok := filtered[s]
+		if !ok || utils.References(filtered)[s] > 0 {
+			continue
+		}
+		delete(filtered, s)
+		for _, f := range fields {
+			if ref := utils.StructRef(f.DataType, configs); ref != "" {
+				dropped = append(dropped, ref)
+			}
+		}
+	}
+	return filtered
+}